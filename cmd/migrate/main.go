@@ -0,0 +1,89 @@
+// Command migrate upgrades mock files, and the files they include, to the current version of the mock format,
+// and writes them back.
+//
+//	go run github.com/mockingio/engine/cmd/migrate [-check] files...
+//
+// With -check, the files are not written, and the command fails if one of them is outdated.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/mockingio/engine/mock"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	check := flags.Bool("check", false, "report the outdated files without writing them, and fail if there is one")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: migrate [-check] files...")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	status := 0
+	for _, file := range flags.Args() {
+		migrated, err := migrateFile(file, *check)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", file, err)
+			status = 1
+			continue
+		}
+
+		for _, f := range migrated {
+			for _, migration := range f.Applied {
+				fmt.Fprintf(stdout, "%s: %s\n", f.Name, migration)
+			}
+		}
+		if *check && len(migrated) > 0 {
+			status = 1
+		}
+	}
+
+	return status
+}
+
+// migrateFile migrates the mock file and the files it includes, it returns the files which are outdated
+func migrateFile(file string, check bool) ([]mock.MigratedFile, error) {
+	if _, err := os.Stat(file); err != nil {
+		return nil, err
+	}
+
+	migrated, err := mock.MigrateFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if check {
+		return migrated, nil
+	}
+
+	for _, f := range migrated {
+		info, err := os.Stat(f.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := ioutil.WriteFile(f.Name, f.Data, info.Mode().Perm()); err != nil {
+			return nil, errors.Wrap(err, "write mock file")
+		}
+	}
+
+	return migrated, nil
+}
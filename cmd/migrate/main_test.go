@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mockingio/engine/mock"
+)
+
+const outdated = `# the shop mock
+name: Shop
+auto_cors: true
+routes:
+  - path: /hello
+    responses:
+      - status: 200
+        headers:
+          Content-Type: text/plain # the greeting
+`
+
+func TestRun(t *testing.T) {
+	t.Run("migrate and write the files back", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "mock.yml")
+		require.NoError(t, ioutil.WriteFile(file, []byte(outdated), 0o600))
+
+		var stdout, stderr bytes.Buffer
+		assert.Equal(t, 0, run([]string{file}, &stdout, &stderr))
+		assert.Equal(t, file+": 1 to 2: auto_cors becomes a cors policy\n"+
+			file+": 2 to 3: the response headers become lists of values\n", stdout.String())
+		assert.Empty(t, stderr.String())
+
+		data, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, `# the shop mock
+version: 3
+name: Shop
+cors:
+  enabled: true
+  preflight_only: true
+routes:
+  - path: /hello
+    responses:
+      - status: 200
+        headers:
+          Content-Type: [text/plain] # the greeting
+`, string(data))
+
+		stdout.Reset()
+		assert.Equal(t, 0, run([]string{"-check", file}, &stdout, &stderr))
+		assert.Empty(t, stdout.String())
+	})
+
+	t.Run("check does not write the files", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "mock.yml")
+		require.NoError(t, ioutil.WriteFile(file, []byte(outdated), 0o600))
+
+		var stdout, stderr bytes.Buffer
+		assert.Equal(t, 1, run([]string{"-check", file}, &stdout, &stderr))
+		assert.Equal(t, file+": 1 to 2: auto_cors becomes a cors policy\n"+
+			file+": 2 to 3: the response headers become lists of values\n", stdout.String())
+
+		data, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, outdated, string(data))
+	})
+
+	t.Run("the included files are migrated too", func(t *testing.T) {
+		dir := t.TempDir()
+		file := filepath.Join(dir, "mock.yml")
+		users := filepath.Join(dir, "routes", "users.yml")
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "routes"), 0o700))
+		require.NoError(t, ioutil.WriteFile(file, []byte(`name: Shop
+routes:
+  - $ref: routes/*.yml
+`), 0o600))
+		require.NoError(t, ioutil.WriteFile(users, []byte(`path: /users
+responses:
+  - status: 200
+    headers:
+      Content-Type: application/json
+`), 0o600))
+
+		var stdout, stderr bytes.Buffer
+		assert.Equal(t, 0, run([]string{file}, &stdout, &stderr))
+		assert.Equal(t, file+": 1 to 2: auto_cors becomes a cors policy\n"+
+			file+": 2 to 3: the response headers become lists of values\n"+
+			users+": 2 to 3: the response headers become lists of values\n", stdout.String())
+		assert.Empty(t, stderr.String())
+
+		data, err := ioutil.ReadFile(users)
+		require.NoError(t, err)
+		assert.Equal(t, `path: /users
+responses:
+  - status: 200
+    headers:
+      Content-Type: [application/json]
+`, string(data))
+
+		mok, err := mock.FromFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, 3, mok.Version)
+		assert.Equal(t, []string{"application/json"}, mok.Routes[0].Responses[0].Headers["Content-Type"])
+
+		stdout.Reset()
+		assert.Equal(t, 0, run([]string{"-check", file}, &stdout, &stderr))
+		assert.Empty(t, stdout.String())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		assert.Equal(t, 2, run(nil, &stdout, &stderr))
+		assert.Equal(t, 1, run([]string{filepath.Join(t.TempDir(), "missing.yml")}, &stdout, &stderr))
+		assert.Contains(t, stderr.String(), "missing.yml: stat")
+	})
+}
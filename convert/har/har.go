@@ -0,0 +1,53 @@
+package har
+
+// HAR is the subset of the HTTP Archive format needed to convert it to a mock
+type HAR struct {
+	Log Log `json:"log"`
+}
+
+type Log struct {
+	Pages   []Page  `json:"pages,omitempty"`
+	Entries []Entry `json:"entries"`
+}
+
+type Page struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+type Entry struct {
+	// ResourceType is recorded by Chromium based browsers, e.g. xhr, fetch, script or image
+	ResourceType string   `json:"_resourceType,omitempty"`
+	Request      Request  `json:"request"`
+	Response     Response `json:"response"`
+}
+
+type Request struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	Headers     []NameValue `json:"headers"`
+	QueryString []NameValue `json:"queryString"`
+	PostData    *PostData   `json:"postData,omitempty"`
+}
+
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type Response struct {
+	Status  int         `json:"status"`
+	Headers []NameValue `json:"headers"`
+	Content Content     `json:"content"`
+}
+
+type Content struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
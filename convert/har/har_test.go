@@ -0,0 +1,117 @@
+package har_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/mockingio/engine/convert/har"
+	"github.com/mockingio/engine/matcher"
+	"github.com/mockingio/engine/mock"
+	"github.com/mockingio/engine/persistent/memory"
+	"github.com/mockingio/engine/test"
+)
+
+func TestImport(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("fixtures", "archive.har"))
+	require.NoError(t, err)
+
+	t.Run("import with filters, redaction and path parameters", func(t *testing.T) {
+		mok, err := har.Import(
+			data,
+			har.WithOnlyXHR(),
+			har.WithoutStaticAssets(),
+			har.WithPathParameters(),
+			har.WithRedactedHeaders(),
+		)
+		require.NoError(t, err)
+		require.NoError(t, mok.Validate())
+
+		var goldenFile = filepath.Join("fixtures", "archive.golden.yml")
+		text, _ := yaml.Marshal(mok)
+		test.UpdateGoldenFile(t, goldenFile, text)
+
+		assert.Equal(t, test.ReadGoldenFile(t, goldenFile), string(text))
+	})
+
+	t.Run("import everything", func(t *testing.T) {
+		mok, err := har.Import(data)
+		require.NoError(t, err)
+
+		var paths []string
+		for _, route := range mok.Routes {
+			paths = append(paths, route.Method+" "+route.Path)
+		}
+		assert.Equal(t, []string{
+			"GET /",
+			"GET /app.js",
+			"GET /api/products",
+			"GET /api/products/42",
+			"GET /api/products/3fa85f64-5717-4562-b3fc-2c963f66afa6",
+			"POST /api/login",
+			"GET /api/status",
+		}, paths)
+		assert.Equal(t, []string{"session=abc"}, mok.Routes[2].Responses[0].Headers["Set-Cookie"])
+	})
+
+	t.Run("static assets are dropped", func(t *testing.T) {
+		mok, err := har.Import(data, har.WithoutStaticAssets())
+		require.NoError(t, err)
+		assert.Len(t, mok.Routes, 6)
+	})
+
+	t.Run("with ID generation option", func(t *testing.T) {
+		mok, err := har.Import(data, har.WithMockOptions(mock.WithIDGeneration()))
+		require.NoError(t, err)
+		assert.True(t, mok.ID != "")
+		assert.True(t, mok.Routes[0].ID != "")
+	})
+
+	t.Run("hyphenated body keys and empty query values are matched", func(t *testing.T) {
+		mok, err := har.Import([]byte(`{"log": {"entries": [
+			{
+				"request": {"method": "POST", "url": "https://example.com/users", "postData": {"text": "{\"first-name\": \"Ann\"}"}},
+				"response": {"status": 201, "content": {"text": "ann"}}
+			},
+			{
+				"request": {"method": "POST", "url": "https://example.com/users", "postData": {"text": "{\"first-name\": \"Bob\"}"}},
+				"response": {"status": 201, "content": {"text": "bob"}}
+			},
+			{
+				"request": {"method": "GET", "url": "https://example.com/search?q="},
+				"response": {"status": 200, "content": {"text": "all"}}
+			},
+			{
+				"request": {"method": "GET", "url": "https://example.com/search?q=shoes"},
+				"response": {"status": 200, "content": {"text": "shoes"}}
+			}
+		]}}`))
+		require.NoError(t, err)
+		require.NoError(t, mok.Validate())
+
+		assert.Equal(t, mock.Rule{Target: mock.Body, Modifier: `."first-name"`, Value: "Bob", Operator: mock.Equal}, mok.Routes[0].Responses[1].Rules[0])
+		assert.Equal(t, mock.Rule{Target: mock.QueryString, Modifier: "q", Operator: mock.Exists}, mok.Routes[1].Responses[0].Rules[0])
+
+		req, _ := http.NewRequest(http.MethodPost, "https://example.com/users", strings.NewReader(`{"first-name": "Bob"}`))
+		response, err := matcher.NewRouteMatcher(mok.Routes[0], matcher.Context{HTTPRequest: req}, memory.New()).Match()
+		require.NoError(t, err)
+		require.NotNil(t, response)
+		assert.Equal(t, "bob", response.Body)
+	})
+
+	t.Run("invalid archive", func(t *testing.T) {
+		_, err := har.Import([]byte(`{"log": `))
+		assert.Error(t, err)
+	})
+
+	t.Run("empty archive", func(t *testing.T) {
+		_, err := har.Import([]byte(`{"log": {"entries": []}}`))
+		assert.Error(t, err)
+	})
+}
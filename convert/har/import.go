@@ -0,0 +1,341 @@
+package har
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/mockingio/engine/mock"
+)
+
+const redacted = "REDACTED"
+
+var (
+	numericRegex    = regexp.MustCompile(`^[0-9]+$`)
+	uuidRegex       = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	identifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+	staticExtensions = map[string]bool{
+		".js": true, ".mjs": true, ".css": true, ".map": true,
+		".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".ico": true, ".webp": true, ".avif": true,
+		".woff": true, ".woff2": true, ".ttf": true, ".otf": true, ".eot": true,
+		".mp4": true, ".webm": true, ".mp3": true,
+	}
+	staticResourceTypes = map[string]bool{
+		"script": true, "stylesheet": true, "image": true, "font": true, "media": true,
+	}
+
+	// the body is stored decoded, so the headers describing the transfer are dropped
+	skippedHeaders = map[string]bool{
+		"content-length": true, "content-encoding": true, "transfer-encoding": true, "connection": true, "keep-alive": true,
+	}
+)
+
+// Import converts the entries of a HAR archive to a mock. Entries with the same method and path become
+// a route, the query and body values which differ between the entries become the rules of the responses
+func Import(data []byte, opts ...Option) (*mock.Mock, error) {
+	o := &options{redactedHeaders: map[string]bool{}}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	archive := &HAR{}
+	if err := json.Unmarshal(data, archive); err != nil {
+		return nil, errors.Wrap(err, "decode har")
+	}
+
+	m := mock.New(o.mockOptions...)
+	if len(archive.Log.Pages) > 0 {
+		m.Name = archive.Log.Pages[0].Title
+	}
+
+	var keys []string
+	groups := map[string][]*entry{}
+	for _, e := range archive.Log.Entries {
+		if !o.keep(e) {
+			continue
+		}
+
+		en, err := o.newEntry(e)
+		if err != nil {
+			return nil, err
+		}
+
+		key := en.method + " " + en.path
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], en)
+	}
+
+	for _, key := range keys {
+		m.Routes = append(m.Routes, o.toRoute(groups[key]))
+	}
+
+	m.Normalize()
+	if err := m.Validate(); err != nil {
+		return nil, errors.Wrap(err, "validate converted mock")
+	}
+
+	return m, nil
+}
+
+// entry is a request of the archive, with the values which could be used in rules
+type entry struct {
+	method   string
+	path     string
+	values   map[ruleKey]string
+	response mock.Response
+}
+
+type ruleKey struct {
+	target   mock.Target
+	modifier string
+}
+
+func (o *options) keep(e Entry) bool {
+	if o.onlyXHR && !isXHR(e) {
+		return false
+	}
+
+	if o.skipStaticAssets && isStaticAsset(e) {
+		return false
+	}
+
+	return true
+}
+
+func (o *options) newEntry(e Entry) (*entry, error) {
+	u, err := url.Parse(e.Request.URL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse url %v", e.Request.URL)
+	}
+
+	en := &entry{
+		method: strings.ToUpper(e.Request.Method),
+		path:   u.Path,
+		values: map[ruleKey]string{},
+	}
+	if en.path == "" {
+		en.path = "/"
+	}
+
+	if o.parameterizePaths {
+		segments := strings.Split(en.path, "/")
+		params := 0
+		for i, segment := range segments {
+			if !numericRegex.MatchString(segment) && !uuidRegex.MatchString(segment) {
+				continue
+			}
+
+			params++
+			name := "id"
+			if params > 1 {
+				name = fmt.Sprintf("id%d", params)
+			}
+			segments[i] = ":" + name
+			en.values[ruleKey{mock.RouteParam, name}] = segment
+		}
+		en.path = strings.Join(segments, "/")
+	}
+
+	for name, values := range u.Query() {
+		en.values[ruleKey{mock.QueryString, name}] = values[0]
+	}
+
+	if e.Request.PostData != nil && e.Request.PostData.Text != "" {
+		addBodyValues(en.values, e.Request.PostData.Text)
+	}
+
+	en.response, err = o.toResponse(e.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return en, nil
+}
+
+// addBodyValues adds the top level string fields of a JSON object body, or the whole body otherwise
+func addBodyValues(values map[ruleKey]string, body string) {
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(body), &fields); err != nil {
+		values[ruleKey{mock.Body, ""}] = body
+		return
+	}
+
+	for name, value := range fields {
+		if s, ok := value.(string); ok {
+			values[ruleKey{mock.Body, toJQ(name)}] = s
+		}
+	}
+}
+
+// toJQ returns the jq query of a top level field, the names which are not identifiers are quoted, like ."first-name"
+func toJQ(name string) string {
+	if identifierRegex.MatchString(name) {
+		return "." + name
+	}
+	return "." + strconv.Quote(name)
+}
+
+func (o *options) toResponse(res Response) (mock.Response, error) {
+	response := mock.Response{
+		Status: res.Status,
+		Body:   res.Content.Text,
+	}
+
+	if res.Content.Encoding == "base64" {
+		body, err := base64.StdEncoding.DecodeString(res.Content.Text)
+		if err != nil {
+			return response, errors.Wrap(err, "decode base64 response body")
+		}
+		response.Body = string(body)
+	}
+
+	for _, h := range res.Headers {
+		name := http.CanonicalHeaderKey(h.Name)
+		if skippedHeaders[strings.ToLower(name)] || strings.HasPrefix(name, ":") {
+			continue
+		}
+		if response.Headers == nil {
+			response.Headers = map[string][]string{}
+		}
+
+		value := h.Value
+		if o.redactedHeaders[strings.ToLower(name)] {
+			value = redacted
+		}
+		response.Headers[name] = append(response.Headers[name], value)
+	}
+
+	return response, nil
+}
+
+func (o *options) toRoute(entries []*entry) *mock.Route {
+	route := &mock.Route{
+		Method: entries[0].method,
+		Path:   entries[0].path,
+	}
+
+	differing := differingKeys(entries)
+	seen := map[string]bool{}
+	for _, en := range entries {
+		var rules []mock.Rule
+		for _, key := range differing {
+			value, ok := en.values[key]
+			switch {
+			case !ok:
+				continue
+			case value == "":
+				// an empty value, like ?q=, can not be matched with equal
+				rules = append(rules, mock.Rule{Target: key.target, Modifier: key.modifier, Operator: mock.Exists})
+			default:
+				rules = append(rules, mock.Rule{
+					Target:   key.target,
+					Modifier: key.modifier,
+					Value:    value,
+					Operator: mock.Equal,
+				})
+			}
+		}
+
+		signature := fmt.Sprintf("%v", rules)
+		if seen[signature] {
+			continue
+		}
+		seen[signature] = true
+
+		response := en.response
+		response.Rules = rules
+		if len(rules) > 0 {
+			response.RuleAggregation = mock.And
+		}
+		route.Responses = append(route.Responses, response)
+	}
+
+	// the same request was answered differently over time, replay the answers in order
+	if len(differing) == 0 && len(entries) > 1 {
+		route.ResponseMode = mock.ResponseSequentially
+		route.Responses = nil
+		for _, en := range entries {
+			route.Responses = append(route.Responses, en.response)
+		}
+	}
+
+	return route
+}
+
+// differingKeys returns the keys whose value is not the same in all the entries, in a stable order
+func differingKeys(entries []*entry) []ruleKey {
+	all := map[ruleKey]bool{}
+	for _, en := range entries {
+		for key := range en.values {
+			all[key] = true
+		}
+	}
+
+	var keys []ruleKey
+	for key := range all {
+		first, ok := entries[0].values[key]
+		for _, en := range entries[1:] {
+			value, found := en.values[key]
+			if found != ok || value != first {
+				keys = append(keys, key)
+				break
+			}
+		}
+	}
+
+	targetOrder := map[mock.Target]int{mock.RouteParam: 0, mock.QueryString: 1, mock.Body: 2}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].target != keys[j].target {
+			return targetOrder[keys[i].target] < targetOrder[keys[j].target]
+		}
+		return keys[i].modifier < keys[j].modifier
+	})
+
+	return keys
+}
+
+func isXHR(e Entry) bool {
+	switch e.ResourceType {
+	case "xhr", "fetch":
+		return true
+	}
+
+	for _, h := range e.Request.Headers {
+		if strings.EqualFold(h.Name, "X-Requested-With") && strings.EqualFold(h.Value, "XMLHttpRequest") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isStaticAsset(e Entry) bool {
+	if staticResourceTypes[e.ResourceType] {
+		return true
+	}
+
+	if u, err := url.Parse(e.Request.URL); err == nil && staticExtensions[strings.ToLower(path.Ext(u.Path))] {
+		return true
+	}
+
+	mimeType := strings.ToLower(e.Response.Content.MimeType)
+	for _, prefix := range []string{"image/", "font/", "audio/", "video/", "text/css", "text/javascript", "application/javascript"} {
+		if strings.HasPrefix(mimeType, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,60 @@
+package har
+
+import (
+	"strings"
+
+	"github.com/mockingio/engine/mock"
+)
+
+var defaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+type options struct {
+	onlyXHR           bool
+	skipStaticAssets  bool
+	parameterizePaths bool
+	redactedHeaders   map[string]bool
+	mockOptions       []mock.Option
+}
+
+type Option func(*options)
+
+// WithOnlyXHR keeps only the XHR and fetch requests
+func WithOnlyXHR() Option {
+	return func(o *options) {
+		o.onlyXHR = true
+	}
+}
+
+// WithoutStaticAssets drops the requests for scripts, stylesheets, images, fonts and media
+func WithoutStaticAssets() Option {
+	return func(o *options) {
+		o.skipStaticAssets = true
+	}
+}
+
+// WithPathParameters replaces the numeric and UUID path segments with route params, /users/42 becomes /users/:id
+func WithPathParameters() Option {
+	return func(o *options) {
+		o.parameterizePaths = true
+	}
+}
+
+// WithRedactedHeaders replaces the values of the headers with a placeholder,
+// Authorization, Proxy-Authorization, Cookie, Set-Cookie and X-Api-Key are redacted if no header is given
+func WithRedactedHeaders(headers ...string) Option {
+	return func(o *options) {
+		if len(headers) == 0 {
+			headers = defaultRedactedHeaders
+		}
+		for _, h := range headers {
+			o.redactedHeaders[strings.ToLower(h)] = true
+		}
+	}
+}
+
+// WithMockOptions passes the options to the created mock, e.g. mock.WithIDGeneration()
+func WithMockOptions(opts ...mock.Option) Option {
+	return func(o *options) {
+		o.mockOptions = append(o.mockOptions, opts...)
+	}
+}
@@ -0,0 +1,277 @@
+package mockoon
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/mockingio/engine/mock"
+)
+
+var (
+	targets = map[string]mock.Target{
+		"body":           mock.Body,
+		"query":          mock.QueryString,
+		"header":         mock.Header,
+		"cookie":         mock.Cookie,
+		"params":         mock.RouteParam,
+		"request_number": mock.RequestNumber,
+	}
+
+	identifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	indexRegex      = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// Import converts a Mockoon environment to a mock. Routes and their responses map one to one,
+// the response rules become mock rules and the features without an equivalent are reported as unsupported
+func Import(data []byte, opts ...mock.Option) (*mock.Mock, []string, error) {
+	env := &Environment{}
+	if err := json.Unmarshal(data, env); err != nil {
+		return nil, nil, errors.Wrap(err, "decode mockoon environment")
+	}
+
+	c := &converter{env: env}
+
+	m := mock.New(opts...)
+	m.ID = env.UUID
+	m.Name = env.Name
+	if env.Cors {
+		m.CORS = &mock.CORS{Enabled: true}
+	}
+	if env.Port > 0 {
+		m.Port = strconv.Itoa(env.Port)
+	}
+
+	if env.ProxyMode {
+		m.Proxy = &mock.Proxy{
+			Enabled:         true,
+			Host:            env.ProxyHost,
+			RequestHeaders:  toHeaders(env.ProxyReqHeaders),
+			ResponseHeaders: toHeaders(env.ProxyResHeaders),
+		}
+	}
+
+	for _, route := range env.Routes {
+		if r := c.toRoute(route); r != nil {
+			m.Routes = append(m.Routes, r)
+		}
+	}
+
+	m.Normalize()
+	if err := m.Validate(); err != nil {
+		return nil, c.unsupported, errors.Wrap(err, "validate converted mock")
+	}
+
+	return m, c.unsupported, nil
+}
+
+type converter struct {
+	env         *Environment
+	unsupported []string
+}
+
+func (c *converter) report(name, format string, args ...interface{}) {
+	c.unsupported = append(c.unsupported, fmt.Sprintf("%s: %s", name, fmt.Sprintf(format, args...)))
+}
+
+func (c *converter) toRoute(route Route) *mock.Route {
+	name := strings.ToUpper(route.Method) + " " + route.Endpoint
+	method := strings.ToUpper(route.Method)
+	if method == "ALL" {
+		method = mock.AnyMethod
+	}
+
+	r := &mock.Route{
+		ID:          route.UUID,
+		Method:      method,
+		Path:        toPath(c.env.EndpointPrefix, route.Endpoint),
+		Description: route.Documentation,
+	}
+
+	ignoreRules := false
+	switch route.ResponseMode {
+	case "":
+	case "RANDOM":
+		r.ResponseMode = mock.ResponseRandomly
+		ignoreRules = true
+	case "SEQUENTIAL":
+		r.ResponseMode = mock.ResponseSequentially
+		ignoreRules = true
+	case "DISABLE_RULES":
+		ignoreRules = true
+	default:
+		c.report(name, "response mode %q is not supported", route.ResponseMode)
+	}
+
+	defaultIdx := 0
+	for i, response := range route.Responses {
+		if response.Default {
+			defaultIdx = i
+			break
+		}
+	}
+
+	// Mockoon serves the default response when no rules match, so it goes last and without rules
+	var fallback *mock.Response
+	for i, response := range route.Responses {
+		res := c.toResponse(name, response)
+		switch {
+		case route.ResponseMode == "DISABLE_RULES" && i != defaultIdx:
+		case ignoreRules:
+			res.Rules, res.RuleAggregation = nil, ""
+			r.Responses = append(r.Responses, res)
+		case i == defaultIdx:
+			res.Rules, res.RuleAggregation = nil, ""
+			fallback = &res
+		case len(res.Rules) == 0:
+			c.report(name, "response %q has no rules and is not the default one, it is skipped", response.Label)
+		default:
+			r.Responses = append(r.Responses, res)
+		}
+	}
+	if fallback != nil {
+		r.Responses = append(r.Responses, *fallback)
+	}
+
+	return r
+}
+
+func (c *converter) toResponse(name string, response RouteResponse) mock.Response {
+	res := mock.Response{
+		ID:      response.UUID,
+		Status:  response.StatusCode,
+		Body:    response.Body,
+		Delay:   response.Latency,
+		Headers: toResponseHeaders(c.env.Headers),
+	}
+
+	for k, values := range toResponseHeaders(response.Headers) {
+		if res.Headers == nil {
+			res.Headers = map[string][]string{}
+		}
+		res.Headers[k] = values
+	}
+
+	if response.BodyType != "" && response.BodyType != "INLINE" || response.FilePath != "" {
+		c.report(name, "response %q: file and data bucket bodies are not supported", response.Label)
+	}
+	if !response.DisableTemplating && strings.Contains(response.Body, "{{") {
+		c.report(name, "response %q: templating is not supported, the body is used as is", response.Label)
+	}
+
+	for _, rule := range response.Rules {
+		if r, ok := c.toRule(name, response.Label, rule); ok {
+			res.Rules = append(res.Rules, r)
+		}
+	}
+
+	if len(res.Rules) > 0 {
+		res.RuleAggregation = mock.And
+		if strings.EqualFold(response.RulesOperator, "OR") {
+			res.RuleAggregation = mock.Or
+		}
+	}
+
+	return res
+}
+
+func (c *converter) toRule(name, label string, rule Rule) (mock.Rule, bool) {
+	target, ok := targets[rule.Target]
+	if !ok {
+		c.report(name, "response %q: rule target %q is not supported", label, rule.Target)
+		return mock.Rule{}, false
+	}
+
+	r := mock.Rule{
+		Target:   target,
+		Modifier: rule.Modifier,
+		Value:    rule.Value,
+		Negate:   rule.Invert,
+	}
+	if target == mock.Body && rule.Modifier != "" {
+		r.Modifier = toJQ(rule.Modifier)
+	}
+
+	switch rule.Operator {
+	case "equals", "":
+		r.Operator = mock.Equal
+	case "regex":
+		r.Operator = mock.Regex
+	case "regex_i":
+		r.Operator = mock.Regex
+		r.Value = "(?i)" + rule.Value
+	case "null":
+		r.Operator = mock.Absent
+		r.Value = ""
+	default:
+		c.report(name, "response %q: rule operator %q is not supported", label, rule.Operator)
+		return mock.Rule{}, false
+	}
+
+	return r, true
+}
+
+// toJQ converts a Mockoon object path or JSONPath to a jq query, user.roles.0 becomes .user.roles[0]
+func toJQ(path string) string {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+
+	var query strings.Builder
+	for _, part := range strings.Split(path, ".") {
+		switch {
+		case indexRegex.MatchString(part):
+			query.WriteString("[" + part + "]")
+		case identifierRegex.MatchString(part):
+			query.WriteString("." + part)
+		default:
+			query.WriteString("." + strconv.Quote(part))
+		}
+	}
+
+	return query.String()
+}
+
+func toPath(prefix, endpoint string) string {
+	var parts []string
+	for _, part := range []string{prefix, endpoint} {
+		if part = strings.Trim(part, "/"); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return "/" + strings.Join(parts, "/")
+}
+
+// toResponseHeaders returns the values of the headers by name, a header can be repeated, like Set-Cookie
+func toResponseHeaders(headers []Header) map[string][]string {
+	var values map[string][]string
+	for _, h := range headers {
+		if h.Key == "" {
+			continue
+		}
+		if values == nil {
+			values = map[string][]string{}
+		}
+		values[h.Key] = append(values[h.Key], h.Value)
+	}
+
+	return values
+}
+
+func toHeaders(headers []Header) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	values := map[string]string{}
+	for _, h := range headers {
+		if h.Key != "" {
+			values[h.Key] = h.Value
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,53 @@
+package mockoon
+
+// Environment is the subset of a Mockoon environment needed to convert it to a mock
+type Environment struct {
+	UUID            string   `json:"uuid"`
+	Name            string   `json:"name"`
+	Port            int      `json:"port"`
+	EndpointPrefix  string   `json:"endpointPrefix"`
+	Routes          []Route  `json:"routes"`
+	ProxyMode       bool     `json:"proxyMode"`
+	ProxyHost       string   `json:"proxyHost"`
+	ProxyReqHeaders []Header `json:"proxyReqHeaders"`
+	ProxyResHeaders []Header `json:"proxyResHeaders"`
+	Cors            bool     `json:"cors"`
+	Headers         []Header `json:"headers"`
+}
+
+type Route struct {
+	UUID          string          `json:"uuid"`
+	Documentation string          `json:"documentation"`
+	Method        string          `json:"method"`
+	Endpoint      string          `json:"endpoint"`
+	Responses     []RouteResponse `json:"responses"`
+	ResponseMode  string          `json:"responseMode"`
+}
+
+type RouteResponse struct {
+	UUID              string   `json:"uuid"`
+	Label             string   `json:"label"`
+	StatusCode        int      `json:"statusCode"`
+	Body              string   `json:"body"`
+	BodyType          string   `json:"bodyType"`
+	FilePath          string   `json:"filePath"`
+	Latency           int64    `json:"latency"`
+	Headers           []Header `json:"headers"`
+	Rules             []Rule   `json:"rules"`
+	RulesOperator     string   `json:"rulesOperator"`
+	DisableTemplating bool     `json:"disableTemplating"`
+	Default           bool     `json:"default"`
+}
+
+type Rule struct {
+	Target   string `json:"target"`
+	Modifier string `json:"modifier"`
+	Value    string `json:"value"`
+	Operator string `json:"operator"`
+	Invert   bool   `json:"invert"`
+}
+
+type Header struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
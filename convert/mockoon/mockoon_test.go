@@ -0,0 +1,40 @@
+package mockoon_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/mockingio/engine/convert/mockoon"
+	"github.com/mockingio/engine/test"
+)
+
+func TestImport(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("fixtures", "environment.json"))
+	require.NoError(t, err)
+
+	t.Run("import environment", func(t *testing.T) {
+		mok, unsupported, err := mockoon.Import(data)
+		require.NoError(t, err)
+		require.NoError(t, mok.Validate())
+
+		var goldenFile = filepath.Join("fixtures", "environment.golden.yml")
+		text, _ := yaml.Marshal(mok)
+		test.UpdateGoldenFile(t, goldenFile, text)
+
+		assert.Equal(t, test.ReadGoldenFile(t, goldenFile), string(text))
+		assert.Equal(t, []string{
+			`POST orders: response "Created": templating is not supported, the body is used as is`,
+			`GET status: response "Down": file and data bucket bodies are not supported`,
+		}, unsupported)
+	})
+
+	t.Run("invalid environment", func(t *testing.T) {
+		_, _, err := mockoon.Import([]byte(`{"routes": `))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,216 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Document is the subset of an OpenAPI 3 or Swagger 2 document needed to convert it from and to a mock
+type Document struct {
+	OpenAPI    string               `yaml:"openapi,omitempty" json:"openapi,omitempty"`
+	Swagger    string               `yaml:"swagger,omitempty" json:"swagger,omitempty"`
+	Info       Info                 `yaml:"info" json:"info"`
+	Servers    []Server             `yaml:"servers,omitempty" json:"servers,omitempty"`
+	BasePath   string               `yaml:"basePath,omitempty" json:"basePath,omitempty"`
+	Produces   []string             `yaml:"produces,omitempty" json:"produces,omitempty"`
+	Paths      map[string]*PathItem `yaml:"paths" json:"paths"`
+	Components *Components          `yaml:"components,omitempty" json:"components,omitempty"`
+	// Swagger 2 only, OpenAPI 3 keeps them in the components
+	Definitions map[string]*Schema    `yaml:"definitions,omitempty" json:"definitions,omitempty"`
+	Parameters  map[string]*Parameter `yaml:"parameters,omitempty" json:"parameters,omitempty"`
+	Responses   map[string]*Response  `yaml:"responses,omitempty" json:"responses,omitempty"`
+}
+
+type Info struct {
+	Title       string `yaml:"title" json:"title"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	Version     string `yaml:"version" json:"version"`
+}
+
+type Server struct {
+	URL         string `yaml:"url" json:"url"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+type Components struct {
+	Schemas    map[string]*Schema    `yaml:"schemas,omitempty" json:"schemas,omitempty"`
+	Responses  map[string]*Response  `yaml:"responses,omitempty" json:"responses,omitempty"`
+	Parameters map[string]*Parameter `yaml:"parameters,omitempty" json:"parameters,omitempty"`
+	Examples   map[string]*Example   `yaml:"examples,omitempty" json:"examples,omitempty"`
+	Headers    map[string]*Header    `yaml:"headers,omitempty" json:"headers,omitempty"`
+}
+
+type PathItem struct {
+	Summary    string       `yaml:"summary,omitempty" json:"summary,omitempty"`
+	Parameters []*Parameter `yaml:"parameters,omitempty" json:"parameters,omitempty"`
+	Get        *Operation   `yaml:"get,omitempty" json:"get,omitempty"`
+	Put        *Operation   `yaml:"put,omitempty" json:"put,omitempty"`
+	Post       *Operation   `yaml:"post,omitempty" json:"post,omitempty"`
+	Delete     *Operation   `yaml:"delete,omitempty" json:"delete,omitempty"`
+	Options    *Operation   `yaml:"options,omitempty" json:"options,omitempty"`
+	Head       *Operation   `yaml:"head,omitempty" json:"head,omitempty"`
+	Patch      *Operation   `yaml:"patch,omitempty" json:"patch,omitempty"`
+	Trace      *Operation   `yaml:"trace,omitempty" json:"trace,omitempty"`
+}
+
+type Operation struct {
+	OperationID string               `yaml:"operationId,omitempty" json:"operationId,omitempty"`
+	Summary     string               `yaml:"summary,omitempty" json:"summary,omitempty"`
+	Description string               `yaml:"description,omitempty" json:"description,omitempty"`
+	Parameters  []*Parameter         `yaml:"parameters,omitempty" json:"parameters,omitempty"`
+	Responses   map[string]*Response `yaml:"responses" json:"responses"`
+	// Swagger 2 only, OpenAPI 3 keeps them in the response content
+	Produces []string `yaml:"produces,omitempty" json:"produces,omitempty"`
+}
+
+type Parameter struct {
+	Ref         string      `yaml:"$ref,omitempty" json:"$ref,omitempty"`
+	Name        string      `yaml:"name,omitempty" json:"name,omitempty"`
+	In          string      `yaml:"in,omitempty" json:"in,omitempty"`
+	Description string      `yaml:"description,omitempty" json:"description,omitempty"`
+	Required    bool        `yaml:"required,omitempty" json:"required,omitempty"`
+	Schema      *Schema     `yaml:"schema,omitempty" json:"schema,omitempty"`
+	Example     interface{} `yaml:"example,omitempty" json:"example,omitempty"`
+}
+
+type Response struct {
+	Ref         string                `yaml:"$ref,omitempty" json:"$ref,omitempty"`
+	Description string                `yaml:"description" json:"description"`
+	Headers     map[string]*Header    `yaml:"headers,omitempty" json:"headers,omitempty"`
+	Content     map[string]*MediaType `yaml:"content,omitempty" json:"content,omitempty"`
+	// Swagger 2 only, examples are keyed by their mime type
+	Schema   *Schema                `yaml:"schema,omitempty" json:"schema,omitempty"`
+	Examples map[string]interface{} `yaml:"examples,omitempty" json:"examples,omitempty"`
+}
+
+type MediaType struct {
+	Schema   *Schema             `yaml:"schema,omitempty" json:"schema,omitempty"`
+	Example  interface{}         `yaml:"example,omitempty" json:"example,omitempty"`
+	Examples map[string]*Example `yaml:"examples,omitempty" json:"examples,omitempty"`
+}
+
+type Example struct {
+	Ref     string      `yaml:"$ref,omitempty" json:"$ref,omitempty"`
+	Summary string      `yaml:"summary,omitempty" json:"summary,omitempty"`
+	Value   interface{} `yaml:"value,omitempty" json:"value,omitempty"`
+}
+
+type Header struct {
+	Ref         string      `yaml:"$ref,omitempty" json:"$ref,omitempty"`
+	Description string      `yaml:"description,omitempty" json:"description,omitempty"`
+	Schema      *Schema     `yaml:"schema,omitempty" json:"schema,omitempty"`
+	Example     interface{} `yaml:"example,omitempty" json:"example,omitempty"`
+	// Swagger 2 only, OpenAPI 3 describes the header with a schema
+	Type    string      `yaml:"type,omitempty" json:"type,omitempty"`
+	Default interface{} `yaml:"default,omitempty" json:"default,omitempty"`
+}
+
+type Schema struct {
+	Ref         string             `yaml:"$ref,omitempty" json:"$ref,omitempty"`
+	Type        string             `yaml:"type,omitempty" json:"type,omitempty"`
+	Format      string             `yaml:"format,omitempty" json:"format,omitempty"`
+	Description string             `yaml:"description,omitempty" json:"description,omitempty"`
+	Nullable    bool               `yaml:"nullable,omitempty" json:"nullable,omitempty"`
+	Properties  map[string]*Schema `yaml:"properties,omitempty" json:"properties,omitempty"`
+	Items       *Schema            `yaml:"items,omitempty" json:"items,omitempty"`
+	Required    []string           `yaml:"required,omitempty" json:"required,omitempty"`
+	Enum        []interface{}      `yaml:"enum,omitempty" json:"enum,omitempty"`
+	Example     interface{}        `yaml:"example,omitempty" json:"example,omitempty"`
+	Default     interface{}        `yaml:"default,omitempty" json:"default,omitempty"`
+	AllOf       []*Schema          `yaml:"allOf,omitempty" json:"allOf,omitempty"`
+	OneOf       []*Schema          `yaml:"oneOf,omitempty" json:"oneOf,omitempty"`
+	AnyOf       []*Schema          `yaml:"anyOf,omitempty" json:"anyOf,omitempty"`
+}
+
+type operation struct {
+	method    string
+	operation *Operation
+}
+
+// Parse decodes an OpenAPI 3 or Swagger 2 document, written in YAML or JSON
+func Parse(data []byte) (*Document, error) {
+	doc := &Document{}
+	if isJSON(data) {
+		if err := json.Unmarshal(data, doc); err != nil {
+			return nil, errors.Wrap(err, "decode json document")
+		}
+	} else if err := yaml.Unmarshal(data, doc); err != nil {
+		return nil, errors.Wrap(err, "decode yaml document")
+	}
+
+	if !doc.isOpenAPI3() && !doc.isSwagger2() {
+		return nil, errors.New("unsupported document, only OpenAPI 3 and Swagger 2 are supported")
+	}
+
+	return doc, nil
+}
+
+func (d *Document) isOpenAPI3() bool {
+	return strings.HasPrefix(d.OpenAPI, "3.")
+}
+
+func (d *Document) isSwagger2() bool {
+	return d.Swagger == "2.0"
+}
+
+// operations returns the operations of the path in a stable order
+func (p *PathItem) operations() []operation {
+	var ops []operation
+	for _, op := range []operation{
+		{"GET", p.Get},
+		{"PUT", p.Put},
+		{"POST", p.Post},
+		{"DELETE", p.Delete},
+		{"OPTIONS", p.Options},
+		{"HEAD", p.Head},
+		{"PATCH", p.Patch},
+		{"TRACE", p.Trace},
+	} {
+		if op.operation != nil {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+func isJSON(data []byte) bool {
+	text := strings.TrimSpace(string(data))
+	return strings.HasPrefix(text, "{")
+}
+
+// normalize converts the maps decoded by yaml.v2 to maps with string keys, so the value can be encoded to JSON
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for k, item := range v {
+			m[toString(k)] = normalize(item)
+		}
+		return m
+	case map[string]interface{}:
+		m := map[string]interface{}{}
+		for k, item := range v {
+			m[k] = normalize(item)
+		}
+		return m
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = normalize(item)
+		}
+		return items
+	default:
+		return v
+	}
+}
+
+func toString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
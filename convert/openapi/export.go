@@ -0,0 +1,309 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mockingio/engine/mock"
+	"github.com/mockingio/engine/pathpattern"
+)
+
+const openAPIVersion = "3.0.3"
+
+var ruleParameters = map[mock.Target]string{
+	mock.QueryString: "query",
+	mock.Header:      "header",
+	mock.Cookie:      "cookie",
+	mock.RouteParam:  "path",
+}
+
+// Export converts the mock to an OpenAPI 3 document. Routes become paths, rules on the query string,
+// headers and cookies become parameters, and response bodies become examples with an inferred schema.
+// The base path of the mock becomes the URL of the server
+func Export(m *mock.Mock) *Document {
+	doc := &Document{
+		OpenAPI: openAPIVersion,
+		Info: Info{
+			Title:   m.Name,
+			Version: "1.0.0",
+		},
+		Paths: map[string]*PathItem{},
+	}
+	if m.BasePath != "" {
+		doc.Servers = []Server{{URL: m.BasePath}}
+	}
+
+	for _, route := range m.Routes {
+		// a regex path has no path template
+		if route.PathMode == mock.PathRegex {
+			continue
+		}
+
+		path, params := toOpenAPIPath(route.Path)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+
+		// the methods without operation, like ANY, are skipped
+		for _, method := range route.AllowedMethods() {
+			op := item.operation(method)
+			if op == nil {
+				op = &Operation{
+					Summary:   route.Description,
+					Responses: map[string]*Response{},
+				}
+				for _, name := range params {
+					op.addParameter(&Parameter{
+						Name:     name,
+						In:       "path",
+						Required: true,
+						Schema:   &Schema{Type: "string"},
+					})
+				}
+				for _, name := range sortedKeys(route.Query) {
+					schema := &Schema{Type: "string"}
+					if value := route.Query[name]; value != "*" {
+						schema.Enum = []interface{}{value}
+					}
+					op.addParameter(&Parameter{Name: name, In: "query", Required: true, Schema: schema})
+				}
+				if !item.setOperation(method, op) {
+					continue
+				}
+			}
+
+			for _, response := range route.Responses {
+				op.addRuleParameters(response.RuleGroup(), false)
+			}
+			op.addResponses(route.Responses)
+		}
+	}
+
+	return doc
+}
+
+func (p *PathItem) operation(method string) *Operation {
+	for _, op := range p.operations() {
+		if op.method == method {
+			return op.operation
+		}
+	}
+	return nil
+}
+
+func (p *PathItem) setOperation(method string, op *Operation) bool {
+	switch method {
+	case http.MethodGet:
+		p.Get = op
+	case http.MethodPut:
+		p.Put = op
+	case http.MethodPost:
+		p.Post = op
+	case http.MethodDelete:
+		p.Delete = op
+	case http.MethodOptions:
+		p.Options = op
+	case http.MethodHead:
+		p.Head = op
+	case http.MethodPatch:
+		p.Patch = op
+	case http.MethodTrace:
+		p.Trace = op
+	default:
+		return false
+	}
+	return true
+}
+
+func (o *Operation) addParameter(param *Parameter) *Parameter {
+	for _, p := range o.Parameters {
+		if p.In == param.In && strings.EqualFold(p.Name, param.Name) {
+			return p
+		}
+	}
+	o.Parameters = append(o.Parameters, param)
+	return param
+}
+
+// addRuleParameters adds the parameters of the rules of the group and of its nested groups.
+// The rules of a not group are negated, they give no example
+func (o *Operation) addRuleParameters(group mock.RuleGroup, negated bool) {
+	if group.RuleAggregation == mock.Not {
+		negated = !negated
+	}
+	for _, rule := range group.Rules {
+		o.addRuleParameter(rule, negated != rule.Negate)
+	}
+	for _, nested := range group.RuleGroups {
+		o.addRuleParameters(nested, negated)
+	}
+}
+
+func (o *Operation) addRuleParameter(rule mock.Rule, negated bool) {
+	in, ok := ruleParameters[rule.Target]
+	if !ok || rule.Modifier == "" {
+		return
+	}
+
+	param := o.addParameter(&Parameter{
+		Name:     rule.Modifier,
+		In:       in,
+		Required: in == "path",
+		Schema:   &Schema{Type: "string"},
+	})
+
+	if negated {
+		return
+	}
+	if param.Example == nil && rule.Operator == mock.Equal {
+		param.Example = rule.Value
+	}
+	if param.Schema != nil && param.Schema.Description == "" && rule.Operator == mock.Regex {
+		param.Schema.Description = fmt.Sprintf("Matches %s", rule.Value)
+	}
+}
+
+func (o *Operation) addResponses(responses []mock.Response) {
+	examples := map[string][]string{}
+	for i, response := range responses {
+		code := strconv.Itoa(response.Status)
+		res, ok := o.Responses[code]
+		if !ok {
+			res = &Response{Description: http.StatusText(response.Status)}
+			if res.Description == "" {
+				res.Description = "Response"
+			}
+			o.Responses[code] = res
+		}
+
+		for name, value := range response.Headers {
+			if strings.EqualFold(name, "Content-Type") {
+				continue
+			}
+			if res.Headers == nil {
+				res.Headers = map[string]*Header{}
+			}
+			if _, ok := res.Headers[name]; !ok {
+				res.Headers[name] = &Header{Schema: &Schema{Type: "string"}, Example: strings.Join(value, ", ")}
+			}
+		}
+
+		if response.Body == "" {
+			continue
+		}
+
+		contentType, example := toExample(response)
+		if res.Content == nil {
+			res.Content = map[string]*MediaType{}
+		}
+
+		name := response.ID
+		if name == "" {
+			name = fmt.Sprintf("example%d", i+1)
+		}
+		key := code + " " + contentType
+		examples[key] = append(examples[key], name)
+
+		media, ok := res.Content[contentType]
+		if !ok {
+			res.Content[contentType] = &MediaType{Schema: inferSchema(example), Example: example}
+			continue
+		}
+
+		// several responses share the same status, each one becomes a named example
+		if media.Examples == nil {
+			media.Examples = map[string]*Example{examples[key][0]: {Value: media.Example}}
+			media.Example = nil
+		}
+		media.Examples[name] = &Example{Value: example}
+	}
+}
+
+// toExample returns the content type of the response and its body, decoded if it is JSON
+func toExample(response mock.Response) (string, interface{}) {
+	contentType := ""
+	for name, values := range response.Headers {
+		if strings.EqualFold(name, "Content-Type") && len(values) > 0 {
+			contentType = strings.TrimSpace(strings.Split(values[0], ";")[0])
+		}
+	}
+
+	var decoded interface{}
+	isJSONBody := json.Unmarshal([]byte(response.Body), &decoded) == nil
+	if contentType == "" {
+		contentType = "text/plain"
+		if isJSONBody {
+			contentType = "application/json"
+		}
+	}
+
+	if isJSONBody && isJSONContentType(contentType) {
+		return contentType, decoded
+	}
+
+	return contentType, response.Body
+}
+
+// inferSchema builds the schema describing a value decoded from JSON
+func inferSchema(value interface{}) *Schema {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		for name, property := range v {
+			schema.Properties[name] = inferSchema(property)
+		}
+		return schema
+	case []interface{}:
+		schema := &Schema{Type: "array", Items: &Schema{}}
+		if len(v) > 0 {
+			schema.Items = inferSchema(v[0])
+		}
+		return schema
+	case string:
+		return &Schema{Type: "string"}
+	case float64:
+		if v == math.Trunc(v) {
+			return &Schema{Type: "integer"}
+		}
+		return &Schema{Type: "number"}
+	case bool:
+		return &Schema{Type: "boolean"}
+	default:
+		return &Schema{Nullable: true}
+	}
+}
+
+// toOpenAPIPath converts the route path to an OpenAPI path template, /users/:id and /users/:id(\d+) become /users/{id}.
+// Catch-alls and wildcards become parameters as well
+func toOpenAPIPath(path string) (string, []string) {
+	var params []string
+	wildcards := 0
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if name, ok := pathpattern.ParamName(segment); ok {
+			params = append(params, name)
+			segments[i] = "{" + name + "}"
+			continue
+		}
+
+		for strings.Contains(segment, "*") {
+			wildcards++
+			name := "wildcard"
+			if wildcards > 1 {
+				name = fmt.Sprintf("wildcard%d", wildcards)
+			}
+			params = append(params, name)
+			segment = strings.Replace(segment, "*", "{"+name+"}", 1)
+		}
+		segments[i] = segment
+	}
+
+	return strings.Join(segments, "/"), params
+}
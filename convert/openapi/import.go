@@ -0,0 +1,435 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/mockingio/engine/mock"
+)
+
+const maxSchemaDepth = 8
+
+var pathParamRegex = regexp.MustCompile(`\{([^}/]+)\}`)
+
+// Import converts an OpenAPI 3 or Swagger 2 document to a mock,
+// every documented status code of an operation becomes a response of the route
+func Import(data []byte, opts ...mock.Option) (*mock.Mock, error) {
+	doc, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToMock(doc, opts...)
+}
+
+// ToMock converts a parsed document to a mock
+func ToMock(doc *Document, opts ...mock.Option) (*mock.Mock, error) {
+	m := mock.New(opts...)
+	m.Name = doc.Info.Title
+
+	basePath := doc.basePath()
+	for _, path := range sortedKeys(doc.Paths) {
+		item := doc.Paths[path]
+		if item == nil {
+			continue
+		}
+
+		for _, op := range item.operations() {
+			route := &mock.Route{
+				Method:      op.method,
+				Path:        basePath + toRoutePath(path),
+				Description: firstNonEmpty(op.operation.Summary, op.operation.Description, item.Summary),
+			}
+			route.Responses = doc.toResponses(op.operation)
+			m.Routes = append(m.Routes, route)
+		}
+	}
+
+	m.Normalize()
+	if err := m.Validate(); err != nil {
+		return nil, errors.Wrap(err, "validate converted mock")
+	}
+
+	return m, nil
+}
+
+func (d *Document) basePath() string {
+	var base string
+	if d.isSwagger2() {
+		base = d.BasePath
+	} else if len(d.Servers) > 0 {
+		if u, err := url.Parse(d.Servers[0].URL); err == nil {
+			base = u.Path
+		}
+	}
+
+	return strings.TrimSuffix(base, "/")
+}
+
+func (d *Document) toResponses(op *Operation) []mock.Response {
+	codes := sortedKeys(op.Responses)
+	var responses []mock.Response
+	hasDefault := false
+
+	for _, code := range codes {
+		status, ok := toStatus(code)
+		if !ok {
+			// "default" describes the undocumented statuses, only used when nothing else is documented
+			if len(codes) > 1 {
+				continue
+			}
+			status = 200
+		}
+
+		response := d.resolveResponse(op.Responses[code])
+		if response == nil {
+			continue
+		}
+
+		res := mock.Response{
+			Status:  status,
+			Headers: map[string][]string{},
+		}
+		contentType, body := d.toBody(op, response)
+		if contentType != "" {
+			res.Headers["Content-Type"] = []string{contentType}
+		}
+		res.Body = body
+
+		for _, name := range sortedKeys(response.Headers) {
+			if value, ok := d.headerValue(response.Headers[name]); ok {
+				res.Headers[name] = []string{value}
+			}
+		}
+
+		if len(res.Headers) == 0 {
+			res.Headers = nil
+		}
+
+		if !hasDefault && status >= 200 && status < 300 {
+			res.IsDefault = true
+			hasDefault = true
+		}
+
+		responses = append(responses, res)
+	}
+
+	if len(responses) == 0 {
+		responses = append(responses, mock.Response{Status: 200})
+	}
+
+	return responses
+}
+
+// toBody returns the content type, and the body built from the examples or the schema of the response
+func (d *Document) toBody(op *Operation, response *Response) (string, string) {
+	if d.isSwagger2() {
+		produces := op.Produces
+		if len(produces) == 0 {
+			produces = d.Produces
+		}
+
+		if examples := preferredContentTypes(sortedKeys(response.Examples)); len(examples) > 0 {
+			return examples[0], encodeBody(examples[0], normalize(response.Examples[examples[0]]))
+		}
+
+		if response.Schema == nil {
+			return "", ""
+		}
+
+		contentType := "application/json"
+		if produces := preferredContentTypes(produces); len(produces) > 0 {
+			contentType = produces[0]
+		}
+
+		return contentType, encodeBody(contentType, d.exampleFromSchema(response.Schema, 0))
+	}
+
+	contentTypes := preferredContentTypes(sortedKeys(response.Content))
+	if len(contentTypes) == 0 {
+		return "", ""
+	}
+
+	contentType := contentTypes[0]
+	media := response.Content[contentType]
+	if media == nil {
+		return contentType, ""
+	}
+
+	if media.Example != nil {
+		return contentType, encodeBody(contentType, normalize(media.Example))
+	}
+
+	for _, name := range sortedKeys(media.Examples) {
+		if example := d.resolveExample(media.Examples[name]); example != nil && example.Value != nil {
+			return contentType, encodeBody(contentType, normalize(example.Value))
+		}
+	}
+
+	if media.Schema != nil {
+		return contentType, encodeBody(contentType, d.exampleFromSchema(media.Schema, 0))
+	}
+
+	return contentType, ""
+}
+
+func (d *Document) headerValue(header *Header) (string, bool) {
+	header = d.resolveHeader(header)
+	if header == nil {
+		return "", false
+	}
+
+	for _, v := range []interface{}{header.Example, header.Default} {
+		if v != nil {
+			return scalarString(normalize(v)), true
+		}
+	}
+
+	if header.Schema != nil {
+		if v := d.exampleFromSchema(header.Schema, 0); v != nil {
+			return scalarString(v), true
+		}
+	}
+
+	if header.Type != "" {
+		return scalarString(d.exampleFromSchema(&Schema{Type: header.Type}, 0)), true
+	}
+
+	return "", false
+}
+
+// exampleFromSchema builds an example value from the schema, preferring the examples, defaults and enums it declares
+func (d *Document) exampleFromSchema(schema *Schema, depth int) interface{} {
+	schema = d.resolveSchema(schema)
+	if schema == nil || depth > maxSchemaDepth {
+		return nil
+	}
+
+	switch {
+	case schema.Example != nil:
+		return normalize(schema.Example)
+	case schema.Default != nil:
+		return normalize(schema.Default)
+	case len(schema.Enum) > 0:
+		return normalize(schema.Enum[0])
+	case len(schema.AllOf) > 0:
+		merged := map[string]interface{}{}
+		for _, s := range schema.AllOf {
+			if obj, ok := d.exampleFromSchema(s, depth+1).(map[string]interface{}); ok {
+				for k, v := range obj {
+					merged[k] = v
+				}
+			}
+		}
+		return merged
+	case len(schema.OneOf) > 0:
+		return d.exampleFromSchema(schema.OneOf[0], depth+1)
+	case len(schema.AnyOf) > 0:
+		return d.exampleFromSchema(schema.AnyOf[0], depth+1)
+	}
+
+	switch schema.Type {
+	case "object":
+		obj := map[string]interface{}{}
+		for name, property := range schema.Properties {
+			obj[name] = d.exampleFromSchema(property, depth+1)
+		}
+		return obj
+	case "array":
+		if schema.Items == nil {
+			return []interface{}{}
+		}
+		return []interface{}{d.exampleFromSchema(schema.Items, depth+1)}
+	case "string":
+		return exampleString(schema.Format)
+	case "integer":
+		return 0
+	case "number":
+		return 0.0
+	case "boolean":
+		return true
+	case "":
+		if len(schema.Properties) > 0 {
+			return d.exampleFromSchema(&Schema{Type: "object", Properties: schema.Properties}, depth)
+		}
+	}
+
+	return nil
+}
+
+func (d *Document) resolveSchema(schema *Schema) *Schema {
+	for i := 0; schema != nil && schema.Ref != "" && i < maxSchemaDepth; i++ {
+		name := refName(schema.Ref)
+		if d.isSwagger2() {
+			schema = d.Definitions[name]
+		} else if d.Components != nil {
+			schema = d.Components.Schemas[name]
+		} else {
+			return nil
+		}
+	}
+	return schema
+}
+
+func (d *Document) resolveResponse(response *Response) *Response {
+	for i := 0; response != nil && response.Ref != "" && i < maxSchemaDepth; i++ {
+		name := refName(response.Ref)
+		if d.isSwagger2() {
+			response = d.Responses[name]
+		} else if d.Components != nil {
+			response = d.Components.Responses[name]
+		} else {
+			return nil
+		}
+	}
+	return response
+}
+
+func (d *Document) resolveExample(example *Example) *Example {
+	for i := 0; example != nil && example.Ref != "" && i < maxSchemaDepth; i++ {
+		if d.Components == nil {
+			return nil
+		}
+		example = d.Components.Examples[refName(example.Ref)]
+	}
+	return example
+}
+
+func (d *Document) resolveHeader(header *Header) *Header {
+	for i := 0; header != nil && header.Ref != "" && i < maxSchemaDepth; i++ {
+		if d.Components == nil {
+			return nil
+		}
+		header = d.Components.Headers[refName(header.Ref)]
+	}
+	return header
+}
+
+// toRoutePath converts the OpenAPI path template to the route syntax, /users/{id} becomes /users/:id.
+// Parameters which are only part of a segment become wildcards
+func toRoutePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if match := pathParamRegex.FindStringSubmatch(segment); match != nil && match[0] == segment {
+			segments[i] = ":" + match[1]
+			continue
+		}
+		segments[i] = pathParamRegex.ReplaceAllString(segment, "*")
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func toStatus(code string) (int, bool) {
+	code = strings.ToUpper(code)
+	if len(code) == 3 && strings.HasSuffix(code, "XX") {
+		code = code[:1] + "00"
+	}
+
+	status, err := strconv.Atoi(code)
+	if err != nil || status < 100 || status > 599 {
+		return 0, false
+	}
+
+	return status, true
+}
+
+// preferredContentTypes returns the unique content types, JSON ones first
+func preferredContentTypes(contentTypes []string) []string {
+	var json, others []string
+	seen := map[string]bool{}
+	for _, contentType := range contentTypes {
+		if seen[contentType] {
+			continue
+		}
+		seen[contentType] = true
+
+		if isJSONContentType(contentType) {
+			json = append(json, contentType)
+		} else {
+			others = append(others, contentType)
+		}
+	}
+
+	return append(json, others...)
+}
+
+func isJSONContentType(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	return strings.Contains(contentType, "/json") || strings.Contains(contentType, "+json")
+}
+
+func encodeBody(contentType string, value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	if s, ok := value.(string); ok && !isJSONContentType(contentType) {
+		return s
+	}
+
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
+
+func exampleString(format string) string {
+	switch format {
+	case "date-time":
+		return "2022-01-01T00:00:00Z"
+	case "date":
+		return "2022-01-01"
+	case "uuid":
+		return "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+	case "email":
+		return "user@example.com"
+	case "uri", "url":
+		return "https://example.com"
+	case "ipv4":
+		return "127.0.0.1"
+	default:
+		return "string"
+	}
+}
+
+func scalarString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return toString(v)
+	}
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
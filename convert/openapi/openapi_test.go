@@ -0,0 +1,154 @@
+package openapi_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/mockingio/engine/convert/openapi"
+	"github.com/mockingio/engine/mock"
+	"github.com/mockingio/engine/test"
+)
+
+func TestImport(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   string
+		golden string
+	}{
+		{"OpenAPI 3 in YAML", "petstore.yml", "petstore.golden.yml"},
+		{"Swagger 2 in JSON", "swagger.json", "swagger.golden.yml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := ioutil.ReadFile(filepath.Join("fixtures", tt.file))
+			require.NoError(t, err)
+
+			mok, err := openapi.Import(data)
+			require.NoError(t, err)
+			require.NoError(t, mok.Validate())
+
+			var goldenFile = filepath.Join("fixtures", tt.golden)
+			text, _ := yaml.Marshal(mok)
+			test.UpdateGoldenFile(t, goldenFile, text)
+
+			assert.Equal(t, test.ReadGoldenFile(t, goldenFile), string(text))
+		})
+	}
+
+	t.Run("with ID generation option", func(t *testing.T) {
+		data, err := ioutil.ReadFile(filepath.Join("fixtures", "petstore.yml"))
+		require.NoError(t, err)
+
+		mok, err := openapi.Import(data, mock.WithIDGeneration())
+		require.NoError(t, err)
+
+		assert.True(t, mok.ID != "")
+		assert.True(t, mok.Routes[0].ID != "")
+		assert.True(t, mok.Routes[0].Responses[0].ID != "")
+	})
+
+	t.Run("unsupported document", func(t *testing.T) {
+		_, err := openapi.Import([]byte(`swagger: "1.2"`))
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid document", func(t *testing.T) {
+		_, err := openapi.Import([]byte(`{"openapi": `))
+		assert.Error(t, err)
+	})
+
+	t.Run("document without paths", func(t *testing.T) {
+		_, err := openapi.Import([]byte(`openapi: 3.0.0`))
+		assert.Error(t, err)
+	})
+}
+
+func TestExport(t *testing.T) {
+	mok, err := mock.FromFile(filepath.Join("fixtures", "mock.yml"))
+	require.NoError(t, err)
+
+	doc := openapi.Export(mok)
+
+	var goldenFile = filepath.Join("fixtures", "export.golden.yml")
+	text, err := yaml.Marshal(doc)
+	require.NoError(t, err)
+	test.UpdateGoldenFile(t, goldenFile, text)
+
+	assert.Equal(t, test.ReadGoldenFile(t, goldenFile), string(text))
+
+	t.Run("exported document can be imported back", func(t *testing.T) {
+		imported, err := openapi.Import(text)
+		require.NoError(t, err)
+
+		require.Len(t, imported.Routes, 4)
+		assert.Equal(t, "GET", imported.Routes[0].Method)
+		assert.Equal(t, "/api", imported.Routes[0].Path)
+		assert.Equal(t, "POST", imported.Routes[1].Method)
+		assert.Equal(t, "/api", imported.Routes[1].Path)
+		assert.Equal(t, "POST", imported.Routes[2].Method)
+		assert.Equal(t, "/files/:wildcard", imported.Routes[2].Path)
+		assert.Equal(t, "GET", imported.Routes[3].Method)
+		assert.Equal(t, "/users/:id", imported.Routes[3].Path)
+		assert.Equal(t, 200, imported.Routes[3].Responses[0].Status)
+		assert.Equal(t, 404, imported.Routes[3].Responses[1].Status)
+	})
+
+	t.Run("the rules of the groups become parameters", func(t *testing.T) {
+		mok := &mock.Mock{
+			Routes: []*mock.Route{
+				{Method: "GET", Path: "/orders", Responses: []mock.Response{{
+					Status: 200,
+					RuleGroups: []mock.RuleGroup{
+						{
+							RuleAggregation: mock.Or,
+							Rules: []mock.Rule{
+								{Target: mock.QueryString, Modifier: "status", Value: "open", Operator: mock.Equal},
+								{Target: mock.Header, Modifier: "X-Tenant", Value: "acme", Operator: mock.Equal},
+							},
+							RuleGroups: []mock.RuleGroup{{
+								RuleAggregation: mock.Not,
+								Rules: []mock.Rule{
+									{Target: mock.Cookie, Modifier: "beta", Value: "1", Operator: mock.Equal},
+								},
+							}},
+						},
+					},
+				}}},
+			},
+		}
+
+		op := openapi.Export(mok).Paths["/orders"].Get
+		require.NotNil(t, op)
+		require.Len(t, op.Parameters, 3)
+		assert.Equal(t, &openapi.Parameter{Name: "status", In: "query", Schema: &openapi.Schema{Type: "string"}, Example: "open"}, op.Parameters[0])
+		assert.Equal(t, &openapi.Parameter{Name: "X-Tenant", In: "header", Schema: &openapi.Schema{Type: "string"}, Example: "acme"}, op.Parameters[1])
+		assert.Equal(t, &openapi.Parameter{Name: "beta", In: "cookie", Schema: &openapi.Schema{Type: "string"}}, op.Parameters[2])
+	})
+
+	t.Run("the base path becomes the URL of the server", func(t *testing.T) {
+		mok := &mock.Mock{
+			Name:     "Shop",
+			BasePath: "/v1",
+			Routes: []*mock.Route{
+				{Method: "GET", Path: "/users/:id", Responses: []mock.Response{{Status: 200}}},
+			},
+		}
+
+		doc := openapi.Export(mok)
+		assert.Equal(t, []openapi.Server{{URL: "/v1"}}, doc.Servers)
+		assert.Contains(t, doc.Paths, "/users/{id}")
+
+		text, err := yaml.Marshal(doc)
+		require.NoError(t, err)
+		imported, err := openapi.Import(text)
+		require.NoError(t, err)
+		require.Len(t, imported.Routes, 1)
+		assert.Equal(t, "/v1/users/:id", imported.Routes[0].Path)
+	})
+}
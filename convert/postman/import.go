@@ -0,0 +1,203 @@
+package postman
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/mockingio/engine/mock"
+)
+
+var variableRegex = regexp.MustCompile(`^\{\{([^}]+)\}\}$`)
+
+// Import converts the saved examples of a Postman collection to a mock. The requests become routes
+// and their examples become responses, the features without an equivalent are reported as unsupported
+func Import(data []byte, opts ...mock.Option) (*mock.Mock, []string, error) {
+	collection := &Collection{}
+	if err := json.Unmarshal(data, collection); err != nil {
+		return nil, nil, errors.Wrap(err, "decode postman collection")
+	}
+
+	c := &converter{routes: map[string]*mock.Route{}}
+	if len(collection.Auth) > 0 && string(collection.Auth) != "null" {
+		c.report(collection.Info.Name, "collection authorization is not supported")
+	}
+	if len(collection.Event) > 0 {
+		c.report(collection.Info.Name, "collection scripts are not supported")
+	}
+	for _, item := range collection.Item {
+		c.addItem("", item)
+	}
+
+	m := mock.New(opts...)
+	m.Name = collection.Info.Name
+	for _, key := range c.keys {
+		m.Routes = append(m.Routes, c.routes[key])
+	}
+
+	m.Normalize()
+	if err := m.Validate(); err != nil {
+		return nil, c.unsupported, errors.Wrap(err, "validate converted mock")
+	}
+
+	return m, c.unsupported, nil
+}
+
+type converter struct {
+	keys        []string
+	routes      map[string]*mock.Route
+	unsupported []string
+}
+
+func (c *converter) report(name, format string, args ...interface{}) {
+	c.unsupported = append(c.unsupported, fmt.Sprintf("%s: %s", name, fmt.Sprintf(format, args...)))
+}
+
+func (c *converter) addItem(folder string, item Item) {
+	name := item.Name
+	if folder != "" {
+		name = folder + "/" + item.Name
+	}
+
+	if len(item.Event) > 0 {
+		c.report(name, "scripts are not supported")
+	}
+
+	if item.Request == nil {
+		for _, child := range item.Item {
+			c.addItem(name, child)
+		}
+		return
+	}
+
+	if len(item.Request.Auth) > 0 && string(item.Request.Auth) != "null" {
+		c.report(name, "request authorization is not supported")
+	}
+
+	if len(item.Response) == 0 {
+		c.report(name, "request has no saved examples, it is skipped")
+		return
+	}
+
+	for _, example := range item.Response {
+		request := item.Request
+		if example.OriginalRequest != nil {
+			request = example.OriginalRequest
+		}
+
+		method := strings.ToUpper(request.Method)
+		if method == "" {
+			method = strings.ToUpper(item.Request.Method)
+		}
+		path := toPath(request.URL)
+
+		key := method + " " + path
+		route, ok := c.routes[key]
+		if !ok {
+			route = &mock.Route{Method: method, Path: path, Description: item.Name}
+			c.routes[key] = route
+			c.keys = append(c.keys, key)
+		}
+
+		response := mock.Response{
+			Status: example.Code,
+			Body:   example.Body,
+		}
+		for _, h := range example.Header {
+			if h.Disabled {
+				continue
+			}
+			if response.Headers == nil {
+				response.Headers = map[string][]string{}
+			}
+			response.Headers[h.Key] = append(response.Headers[h.Key], h.Value)
+		}
+
+		for _, q := range toQuery(request.URL) {
+			// a param without value, like ?debug, can not be matched with equal
+			if q.Value == "" {
+				response.Rules = append(response.Rules, mock.Rule{Target: mock.QueryString, Modifier: q.Key, Operator: mock.Exists})
+				continue
+			}
+			response.Rules = append(response.Rules, mock.Rule{
+				Target:   mock.QueryString,
+				Modifier: q.Key,
+				Value:    q.Value,
+				Operator: mock.Equal,
+			})
+		}
+		if len(response.Rules) > 0 {
+			response.RuleAggregation = mock.And
+		}
+
+		if request.Body != nil && request.Body.Mode != "" && request.Body.Mode != "raw" {
+			c.report(name, "example %q: %s request body is not supported", example.Name, request.Body.Mode)
+		}
+
+		route.Responses = append(route.Responses, response)
+	}
+}
+
+// toPath returns the path of the request URL, Postman variables like {{id}} become route params
+func toPath(u URL) string {
+	segments := append([]string(nil), u.Path...)
+	if len(segments) == 0 {
+		raw := u.Raw
+		if i := strings.IndexAny(raw, "?#"); i >= 0 {
+			raw = raw[:i]
+		}
+		if i := strings.Index(raw, "://"); i >= 0 {
+			raw = raw[i+3:]
+		}
+		// drop the host, which is usually a variable like {{baseUrl}}
+		if strings.HasPrefix(raw, "{{") {
+			raw = raw[strings.Index(raw, "}}")+2:]
+		} else if !strings.HasPrefix(raw, "/") {
+			raw = raw[len(strings.SplitN(raw, "/", 2)[0]):]
+		}
+		segments = strings.Split(strings.Trim(raw, "/"), "/")
+	}
+
+	for i, segment := range segments {
+		if match := variableRegex.FindStringSubmatch(segment); match != nil {
+			segments[i] = ":" + match[1]
+		}
+	}
+
+	return "/" + strings.Join(segments, "/")
+}
+
+func toQuery(u URL) []KeyValue {
+	if len(u.Query) > 0 || u.Raw == "" {
+		var query []KeyValue
+		for _, q := range u.Query {
+			if !q.Disabled {
+				query = append(query, q)
+			}
+		}
+		return query
+	}
+
+	idx := strings.Index(u.Raw, "?")
+	if idx < 0 {
+		return nil
+	}
+
+	var query []KeyValue
+	for _, part := range strings.Split(u.Raw[idx+1:], "&") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		q := KeyValue{Key: kv[0]}
+		if len(kv) == 2 {
+			q.Value = kv[1]
+		}
+		query = append(query, q)
+	}
+
+	return query
+}
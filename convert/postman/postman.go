@@ -0,0 +1,77 @@
+package postman
+
+import (
+	"encoding/json"
+)
+
+// Collection is the subset of a Postman collection (v2.0 and v2.1) needed to convert its saved examples to a mock
+type Collection struct {
+	Info     Info            `json:"info"`
+	Item     []Item          `json:"item"`
+	Auth     json.RawMessage `json:"auth,omitempty"`
+	Event    []Event         `json:"event,omitempty"`
+	Variable []KeyValue      `json:"variable,omitempty"`
+}
+
+type Info struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+// Item is a request with its saved examples, or a folder of items
+type Item struct {
+	Name     string    `json:"name"`
+	Item     []Item    `json:"item,omitempty"`
+	Request  *Request  `json:"request,omitempty"`
+	Response []Example `json:"response,omitempty"`
+	Event    []Event   `json:"event,omitempty"`
+}
+
+type Request struct {
+	Method string          `json:"method"`
+	URL    URL             `json:"url"`
+	Header []KeyValue      `json:"header,omitempty"`
+	Body   *Body           `json:"body,omitempty"`
+	Auth   json.RawMessage `json:"auth,omitempty"`
+}
+
+// URL is either a raw string, or the URL split in its parts
+type URL struct {
+	Raw   string     `json:"raw"`
+	Path  []string   `json:"path,omitempty"`
+	Query []KeyValue `json:"query,omitempty"`
+}
+
+type Body struct {
+	Mode string `json:"mode"`
+	Raw  string `json:"raw,omitempty"`
+}
+
+type Example struct {
+	Name            string     `json:"name"`
+	OriginalRequest *Request   `json:"originalRequest,omitempty"`
+	Code            int        `json:"code"`
+	Header          []KeyValue `json:"header,omitempty"`
+	Body            string     `json:"body,omitempty"`
+}
+
+type KeyValue struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+type Event struct {
+	Listen string `json:"listen"`
+}
+
+func (u *URL) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		u.Raw = raw
+		return nil
+	}
+
+	type plain URL
+	return json.Unmarshal(data, (*plain)(u))
+}
@@ -0,0 +1,70 @@
+package postman_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/mockingio/engine/convert/postman"
+	"github.com/mockingio/engine/mock"
+	"github.com/mockingio/engine/test"
+)
+
+func TestImport(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("fixtures", "collection.json"))
+	require.NoError(t, err)
+
+	t.Run("import collection", func(t *testing.T) {
+		mok, unsupported, err := postman.Import(data)
+		require.NoError(t, err)
+		require.NoError(t, mok.Validate())
+
+		var goldenFile = filepath.Join("fixtures", "collection.golden.yml")
+		text, _ := yaml.Marshal(mok)
+		test.UpdateGoldenFile(t, goldenFile, text)
+
+		assert.Equal(t, test.ReadGoldenFile(t, goldenFile), string(text))
+		assert.Equal(t, []string{
+			"Users/Get user: scripts are not supported",
+			"Users/Get user: request authorization is not supported",
+			`Users/Upload avatar: example "Uploaded": formdata request body is not supported`,
+			"Delete user: request has no saved examples, it is skipped",
+		}, unsupported)
+	})
+
+	t.Run("with ID generation option", func(t *testing.T) {
+		mok, _, err := postman.Import(data, mock.WithIDGeneration())
+		require.NoError(t, err)
+		assert.True(t, mok.ID != "")
+		assert.True(t, mok.Routes[0].Responses[0].ID != "")
+	})
+
+	t.Run("query params without value must exist", func(t *testing.T) {
+		mok, _, err := postman.Import([]byte(`{"item": [
+			{
+				"name": "Search",
+				"request": {"method": "GET", "url": {"raw": "https://example.com/search?debug"}},
+				"response": [{"name": "Debug", "code": 200}]
+			},
+			{
+				"name": "List",
+				"request": {"method": "GET", "url": {"raw": "https://example.com/items?page=", "query": [{"key": "page", "value": null}]}},
+				"response": [{"name": "All", "code": 200}]
+			}
+		]}`))
+		require.NoError(t, err)
+		require.NoError(t, mok.Validate())
+
+		assert.Equal(t, []mock.Rule{{Target: mock.QueryString, Modifier: "debug", Operator: mock.Exists}}, mok.Routes[0].Responses[0].Rules)
+		assert.Equal(t, []mock.Rule{{Target: mock.QueryString, Modifier: "page", Operator: mock.Exists}}, mok.Routes[1].Responses[0].Rules)
+	})
+
+	t.Run("invalid collection", func(t *testing.T) {
+		_, _, err := postman.Import([]byte(`{"item": `))
+		assert.Error(t, err)
+	})
+}
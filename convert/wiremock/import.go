@@ -0,0 +1,420 @@
+package wiremock
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/mockingio/engine/mock"
+)
+
+const defaultPriority = 5
+
+var (
+	jsonPathRegex     = regexp.MustCompile(`^\$((\.[A-Za-z_][A-Za-z0-9_]*)|(\[[0-9]+\]))+$`)
+	pathWildcardRegex = regexp.MustCompile(`\[\^/\]\+|\.\*|\.\+`)
+	regexMetaRegex    = regexp.MustCompile(`[\\^$.|?*+()\[\]{}]`)
+)
+
+// Import converts WireMock stub mappings to a mock. It accepts a mappings file or a single mapping.
+// The request matchers become rules, and the features without an equivalent are reported as unsupported
+func Import(data []byte, opts ...mock.Option) (*mock.Mock, []string, error) {
+	mappings := &Mappings{}
+	if err := json.Unmarshal(data, mappings); err != nil {
+		return nil, nil, errors.Wrap(err, "decode wiremock mappings")
+	}
+
+	if mappings.Mappings == nil {
+		single := Mapping{}
+		if err := json.Unmarshal(data, &single); err != nil {
+			return nil, nil, errors.Wrap(err, "decode wiremock mapping")
+		}
+		mappings.Mappings = []Mapping{single}
+	}
+
+	c := &converter{routes: map[string]*route{}}
+	for i, mapping := range mappings.Mappings {
+		c.addMapping(i, mapping)
+	}
+
+	m := mock.New(opts...)
+	for _, key := range c.keys {
+		m.Routes = append(m.Routes, c.routes[key].toRoute())
+	}
+
+	m.Normalize()
+	if err := m.Validate(); err != nil {
+		return nil, c.unsupported, errors.Wrap(err, "validate converted mock")
+	}
+
+	return m, c.unsupported, nil
+}
+
+type converter struct {
+	keys        []string
+	routes      map[string]*route
+	unsupported []string
+}
+
+type route struct {
+	method    string
+	path      string
+	regex     bool
+	responses []prioritizedResponse
+}
+
+type prioritizedResponse struct {
+	priority int
+	index    int
+	response mock.Response
+}
+
+func (c *converter) addMapping(index int, mapping Mapping) {
+	name := mapping.Name
+	if name == "" {
+		name = fmt.Sprintf("mapping %d", index+1)
+	}
+	report := func(format string, args ...interface{}) {
+		c.unsupported = append(c.unsupported, fmt.Sprintf("%s: %s", name, fmt.Sprintf(format, args...)))
+	}
+
+	method := strings.ToUpper(mapping.Request.Method)
+	if method == "" {
+		method = mock.AnyMethod
+	}
+
+	path, regex, rules, ok := toPath(mapping.Request, report)
+	if !ok {
+		return
+	}
+
+	for _, name := range sortedKeys(mapping.Request.QueryParameters) {
+		rules = append(rules, toRules(mock.QueryString, name, mapping.Request.QueryParameters[name], report)...)
+	}
+	for _, name := range sortedKeys(mapping.Request.Headers) {
+		rules = append(rules, toRules(mock.Header, name, mapping.Request.Headers[name], report)...)
+	}
+	for _, name := range sortedKeys(mapping.Request.Cookies) {
+		rules = append(rules, toRules(mock.Cookie, name, mapping.Request.Cookies[name], report)...)
+	}
+	for _, matcher := range mapping.Request.BodyPatterns {
+		rules = append(rules, toBodyRules(matcher, report)...)
+	}
+
+	if len(mapping.Request.BasicAuthCredentials) > 0 {
+		report("basicAuthCredentials matching is not supported")
+	}
+	if len(mapping.PostServeActions) > 0 {
+		report("postServeActions are not supported")
+	}
+
+	response := toResponse(mapping.Response, report)
+	response.ID = mapping.ID
+	response.Rules = rules
+	if mapping.ScenarioName != "" {
+		response.Scenario = mapping.ScenarioName
+		response.RequiredState = mapping.RequiredScenarioState
+		response.NewState = mapping.NewScenarioState
+	}
+	if len(rules) > 0 {
+		response.RuleAggregation = mock.And
+	}
+
+	priority := mapping.Priority
+	if priority == 0 {
+		priority = defaultPriority
+	}
+
+	key := fmt.Sprintf("%s %s %t", method, path, regex)
+	r, ok := c.routes[key]
+	if !ok {
+		r = &route{method: method, path: path, regex: regex}
+		c.routes[key] = r
+		c.keys = append(c.keys, key)
+	}
+	r.responses = append(r.responses, prioritizedResponse{priority: priority, index: index, response: response})
+}
+
+// toRoute orders the responses like WireMock picks the stubs, by priority then the most recently added first
+func (r *route) toRoute() *mock.Route {
+	sort.SliceStable(r.responses, func(i, j int) bool {
+		if r.responses[i].priority != r.responses[j].priority {
+			return r.responses[i].priority < r.responses[j].priority
+		}
+		return r.responses[i].index > r.responses[j].index
+	})
+
+	res := &mock.Route{Method: r.method, Path: r.path}
+	if r.regex {
+		res.PathMode = mock.PathRegex
+	}
+	for _, response := range r.responses {
+		res.Responses = append(res.Responses, response.response)
+	}
+
+	return res
+}
+
+// toPath returns the path of the request pattern, regex is true if the path is a regex
+func toPath(req RequestPattern, report func(string, ...interface{})) (path string, regex bool, rules []mock.Rule, ok bool) {
+	switch {
+	case req.URLPath != "":
+		return req.URLPath, false, nil, true
+	case req.URL != "":
+		u, err := url.Parse(req.URL)
+		if err != nil {
+			report("url %q can not be parsed, the mapping is skipped", req.URL)
+			return "", false, nil, false
+		}
+
+		query := u.Query()
+		for _, name := range sortedKeys(query) {
+			values := query[name]
+			if len(values) == 1 {
+				rules = append(rules, mock.Rule{Target: mock.QueryString, Modifier: name, Value: values[0], Operator: mock.Equal})
+				continue
+			}
+
+			// a repeated param matches each value at its index
+			for i, value := range values {
+				rules = append(rules, mock.Rule{Target: mock.QueryString, Modifier: name, Value: value, Operator: mock.Equal, MultiValue: strconv.Itoa(i)})
+			}
+		}
+		return u.Path, false, rules, true
+	case req.URLPathPattern != "" || req.URLPattern != "":
+		pattern := req.URLPathPattern
+		if pattern == "" {
+			pattern = req.URLPattern
+		}
+
+		// the patterns made of literal segments and wildcards have an equivalent path, the others are matched as regex
+		path := pathWildcardRegex.ReplaceAllString(strings.TrimSuffix(strings.TrimPrefix(pattern, "^"), "$"), "*")
+		if !regexMetaRegex.MatchString(strings.ReplaceAll(path, "*", "")) {
+			return path, false, nil, true
+		}
+		if strings.Contains(pattern, `\?`) {
+			report("url pattern %q matching the query string is not supported, the mapping is skipped", pattern)
+			return "", false, nil, false
+		}
+		return strings.TrimSuffix(strings.TrimPrefix(pattern, "^"), "$"), true, nil, true
+	default:
+		return "/*", false, nil, true
+	}
+}
+
+func toRules(target mock.Target, name string, matcher Matcher, report func(string, ...interface{})) []mock.Rule {
+	var rules []mock.Rule
+	caseInsensitive, _ := matcher["caseInsensitive"].(bool)
+
+	for _, operator := range sortedKeys(matcher) {
+		value := matcher[operator]
+		switch operator {
+		case "caseInsensitive":
+		case "equalTo":
+			if caseInsensitive {
+				rules = append(rules, mock.Rule{Target: target, Modifier: name, Value: toString(value), Operator: mock.CaseInsensitiveEqual})
+			} else {
+				rules = append(rules, mock.Rule{Target: target, Modifier: name, Value: toString(value), Operator: mock.Equal})
+			}
+		case "matches":
+			rules = append(rules, mock.Rule{Target: target, Modifier: name, Value: anchored(toString(value)), Operator: mock.Regex})
+		case "doesNotMatch":
+			rules = append(rules, mock.Rule{Target: target, Modifier: name, Value: anchored(toString(value)), Operator: mock.Regex, Negate: true})
+		case "contains":
+			rules = append(rules, mock.Rule{Target: target, Modifier: name, Value: toString(value), Operator: mock.Contains})
+		case "absent":
+			if absent, _ := value.(bool); absent {
+				rules = append(rules, mock.Rule{Target: target, Modifier: name, Operator: mock.Absent})
+			} else {
+				rules = append(rules, mock.Rule{Target: target, Modifier: name, Operator: mock.Exists})
+			}
+		case "includes", "hasExactly":
+			// each matcher matches one of the values, hasExactly also matches the number of values
+			matchers, _ := value.([]interface{})
+			for _, m := range matchers {
+				item, _ := m.(map[string]interface{})
+				for _, rule := range toRules(target, name, item, report) {
+					rule.MultiValue = mock.AnyValue
+					rules = append(rules, rule)
+				}
+			}
+			if operator == "hasExactly" {
+				rules = append(rules, mock.Rule{Target: target, Modifier: name, Value: strconv.Itoa(len(matchers)), Operator: mock.Equal, MultiValue: mock.ValueCount})
+			}
+		default:
+			report("%s matcher %q on %q is not supported", target, operator, name)
+		}
+	}
+
+	return rules
+}
+
+func toBodyRules(matcher Matcher, report func(string, ...interface{})) []mock.Rule {
+	if expression, ok := matcher["matchesJsonPath"]; ok {
+		return toJSONPathRules(expression, report)
+	}
+
+	if document, ok := matcher["equalToJson"]; ok {
+		return toJSONRules(document, matcher, report)
+	}
+
+	for _, operator := range []string{"equalToXml", "matchesXPath", "binaryEqualTo", "absent"} {
+		if _, ok := matcher[operator]; ok {
+			report("body matcher %q is not supported", operator)
+			return nil
+		}
+	}
+
+	return toRules(mock.Body, "", matcher, report)
+}
+
+// toJSONPathRules converts a JSONPath with a value matcher to jq rules, $.user.name becomes .user.name
+func toJSONPathRules(expression interface{}, report func(string, ...interface{})) []mock.Rule {
+	obj, ok := expression.(map[string]interface{})
+	if !ok {
+		report("matchesJsonPath %q without a value matcher is not supported", toString(expression))
+		return nil
+	}
+
+	path := toString(obj["expression"])
+	if !jsonPathRegex.MatchString(path) {
+		report("JSONPath expression %q is not supported", path)
+		return nil
+	}
+
+	matcher := Matcher{}
+	for k, v := range obj {
+		if k != "expression" {
+			matcher[k] = v
+		}
+	}
+
+	return toRules(mock.Body, strings.TrimPrefix(path, "$"), matcher, report)
+}
+
+func toResponse(def ResponseDefinition, report func(string, ...interface{})) mock.Response {
+	response := mock.Response{
+		Status: def.Status,
+		Body:   def.Body,
+		Delay:  def.FixedDelayMilliseconds,
+	}
+
+	if def.JSONBody != nil {
+		data, err := json.MarshalIndent(def.JSONBody, "", "  ")
+		if err == nil {
+			response.Body = string(data)
+		}
+	}
+
+	if def.Base64Body != "" {
+		data, err := base64.StdEncoding.DecodeString(def.Base64Body)
+		if err != nil {
+			report("base64Body can not be decoded")
+		}
+		response.Body = string(data)
+	}
+
+	for name, value := range def.Headers {
+		if response.Headers == nil {
+			response.Headers = map[string][]string{}
+		}
+		if values, ok := value.([]interface{}); ok {
+			for _, v := range values {
+				response.Headers[name] = append(response.Headers[name], toString(v))
+			}
+			continue
+		}
+		response.Headers[name] = []string{toString(value)}
+	}
+
+	if def.BodyFileName != "" {
+		report("bodyFileName %q is not supported", def.BodyFileName)
+	}
+	if def.Fault != "" {
+		report("fault %q is not supported", def.Fault)
+	}
+	if def.ProxyBaseURL != "" {
+		report("proxyBaseUrl is not supported")
+	}
+	if len(def.Transformers) > 0 {
+		report("response transformers %v are not supported", def.Transformers)
+	}
+
+	return response
+}
+
+// anchored makes the regex match the whole value, like WireMock does
+func anchored(pattern string) string {
+	return "^(?:" + pattern + ")$"
+}
+
+// toJSONRules converts equalToJson, the extra elements and the array order are ignored by the json_contains operator
+func toJSONRules(document interface{}, matcher Matcher, report func(string, ...interface{})) []mock.Rule {
+	if text, ok := document.(string); ok {
+		if err := json.Unmarshal([]byte(text), &document); err != nil {
+			report("equalToJson %q is not valid JSON", text)
+			return nil
+		}
+	}
+
+	operator := mock.JSONEquals
+	ignoreArrayOrder, _ := matcher["ignoreArrayOrder"].(bool)
+	ignoreExtraElements, _ := matcher["ignoreExtraElements"].(bool)
+	if ignoreArrayOrder || ignoreExtraElements {
+		operator = mock.JSONContains
+	}
+
+	return []mock.Rule{{
+		Target:   mock.JSONBody,
+		Operator: operator,
+		Value:    toString(toPlaceholders(document)),
+	}}
+}
+
+// toPlaceholders replaces the json-unit placeholders of WireMock with the placeholders of the json operators
+func toPlaceholders(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = toPlaceholders(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = toPlaceholders(item)
+		}
+	case string:
+		switch {
+		case v == "${json-unit.any-string}":
+			return "${regex:(?s).*}"
+		case v == "${json-unit.ignore}" || v == "${json-unit.any-number}" || v == "${json-unit.any-boolean}":
+			return "${any}"
+		case strings.HasPrefix(v, "${json-unit.regex}"):
+			return "${regex:" + strings.TrimPrefix(v, "${json-unit.regex}") + "}"
+		}
+	}
+	return value
+}
+
+func toString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
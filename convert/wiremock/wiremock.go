@@ -0,0 +1,51 @@
+package wiremock
+
+import (
+	"encoding/json"
+)
+
+// Mappings is a WireMock mappings file, as exported by the admin API or stored in the mappings directory
+type Mappings struct {
+	Mappings []Mapping `json:"mappings"`
+}
+
+type Mapping struct {
+	ID                    string             `json:"id,omitempty"`
+	Name                  string             `json:"name,omitempty"`
+	Priority              int                `json:"priority,omitempty"`
+	Request               RequestPattern     `json:"request"`
+	Response              ResponseDefinition `json:"response"`
+	ScenarioName          string             `json:"scenarioName,omitempty"`
+	RequiredScenarioState string             `json:"requiredScenarioState,omitempty"`
+	NewScenarioState      string             `json:"newScenarioState,omitempty"`
+	PostServeActions      json.RawMessage    `json:"postServeActions,omitempty"`
+}
+
+type RequestPattern struct {
+	Method               string             `json:"method,omitempty"`
+	URL                  string             `json:"url,omitempty"`
+	URLPath              string             `json:"urlPath,omitempty"`
+	URLPattern           string             `json:"urlPattern,omitempty"`
+	URLPathPattern       string             `json:"urlPathPattern,omitempty"`
+	QueryParameters      map[string]Matcher `json:"queryParameters,omitempty"`
+	Headers              map[string]Matcher `json:"headers,omitempty"`
+	Cookies              map[string]Matcher `json:"cookies,omitempty"`
+	BodyPatterns         []Matcher          `json:"bodyPatterns,omitempty"`
+	BasicAuthCredentials json.RawMessage    `json:"basicAuthCredentials,omitempty"`
+}
+
+// Matcher is a WireMock value matcher, e.g. {"equalTo": "value"} or {"matches": "[a-z]+"}
+type Matcher map[string]interface{}
+
+type ResponseDefinition struct {
+	Status                 int                    `json:"status,omitempty"`
+	Body                   string                 `json:"body,omitempty"`
+	JSONBody               interface{}            `json:"jsonBody,omitempty"`
+	Base64Body             string                 `json:"base64Body,omitempty"`
+	BodyFileName           string                 `json:"bodyFileName,omitempty"`
+	Headers                map[string]interface{} `json:"headers,omitempty"`
+	FixedDelayMilliseconds int64                  `json:"fixedDelayMilliseconds,omitempty"`
+	Fault                  string                 `json:"fault,omitempty"`
+	ProxyBaseURL           string                 `json:"proxyBaseUrl,omitempty"`
+	Transformers           []string               `json:"transformers,omitempty"`
+}
@@ -0,0 +1,54 @@
+package wiremock_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/mockingio/engine/convert/wiremock"
+	"github.com/mockingio/engine/mock"
+	"github.com/mockingio/engine/test"
+)
+
+func TestImport(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("fixtures", "mappings.json"))
+	require.NoError(t, err)
+
+	t.Run("import mappings", func(t *testing.T) {
+		mok, unsupported, err := wiremock.Import(data)
+		require.NoError(t, err)
+		require.NoError(t, mok.Validate())
+
+		var goldenFile = filepath.Join("fixtures", "mappings.golden.yml")
+		text, _ := yaml.Marshal(mok)
+		test.UpdateGoldenFile(t, goldenFile, text)
+
+		assert.Equal(t, test.ReadGoldenFile(t, goldenFile), string(text))
+		assert.Equal(t, []string{
+			`create order: matchesJsonPath "$.quantity" without a value matcher is not supported`,
+			`create order: bodyFileName "order.json" is not supported`,
+			`checkout: fault "CONNECTION_RESET_BY_PEER" is not supported`,
+		}, unsupported)
+	})
+
+	t.Run("import a single mapping, with ID generation option", func(t *testing.T) {
+		mok, unsupported, err := wiremock.Import([]byte(`{
+			"request": {"method": "GET", "url": "/hello"},
+			"response": {"status": 200, "body": "world"}
+		}`), mock.WithIDGeneration())
+		require.NoError(t, err)
+		assert.Empty(t, unsupported)
+		assert.True(t, mok.ID != "")
+		assert.Equal(t, "/hello", mok.Routes[0].Path)
+		assert.Equal(t, "world", mok.Routes[0].Responses[0].Body)
+	})
+
+	t.Run("invalid mappings", func(t *testing.T) {
+		_, _, err := wiremock.Import([]byte(`{"mappings": `))
+		assert.Error(t, err)
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -15,17 +17,33 @@ import (
 )
 
 type Engine struct {
-	mockID   string
-	isPaused bool
-	db       persistent.Persistent
-	mock     *mock.Mock
+	mockID      string
+	isPaused    bool
+	db          persistent.Persistent
+	mock        *mock.Mock
+	maxBodySize int64
 }
 
-func New(mockID string, db persistent.Persistent) *Engine {
-	return &Engine{
+type Option func(*Engine)
+
+// WithMaxBodySize limits the size of the request bodies read by the rules, matcher.DefaultMaxBodySize if it is not set
+func WithMaxBodySize(size int64) Option {
+	return func(eng *Engine) {
+		eng.maxBodySize = size
+	}
+}
+
+func New(mockID string, db persistent.Persistent, opts ...Option) *Engine {
+	eng := &Engine{
 		mockID: mockID,
 		db:     db,
 	}
+
+	for _, opt := range opts {
+		opt(eng)
+	}
+
+	return eng
 }
 
 func (eng *Engine) Resume() {
@@ -45,17 +63,13 @@ func (eng *Engine) Match(req *http.Request) *mock.Response {
 
 	mok := eng.getMock()
 
-	sessionID, err := eng.db.GetActiveSession(ctx, eng.mockID)
-	if err != nil {
-		log.WithError(err).WithField("config_id", eng.mockID).Error("get active session")
-	}
+	// the body is read once for all the routes, and put back for the proxy
+	matchContext := eng.matchContext(req, mok)
+	matchContext.SessionID = eng.activeSession(ctx)
 
 	for _, route := range mok.Routes {
 		log.Debugf("Matching route: %v %v", route.Method, route.Path)
-		response, err := matcher.NewRouteMatcher(route, matcher.Context{
-			HTTPRequest: req,
-			SessionID:   sessionID,
-		}, eng.db).Match()
+		response, err := matcher.NewRouteMatcher(route, matchContext, eng.db).Match()
 		if err != nil {
 			log.WithError(err).Error("matching route")
 			continue
@@ -76,6 +90,38 @@ func (eng *Engine) Match(req *http.Request) *mock.Response {
 	return nil
 }
 
+// ResetScenarios moves all the scenarios of the mock back to the started state, in the active session
+func (eng *Engine) ResetScenarios(ctx context.Context) error {
+	if err := eng.reloadMock(ctx); err != nil {
+		return errors.Wrap(err, "reload mock")
+	}
+
+	for _, scenario := range eng.getMock().Scenarios() {
+		if err := eng.SetScenarioState(ctx, scenario, mock.StartedState); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetScenarioState moves the scenario to the state, in the active session
+func (eng *Engine) SetScenarioState(ctx context.Context, scenario, state string) error {
+	key := matcher.Context{MockID: eng.mockID, SessionID: eng.activeSession(ctx)}.ScenarioID(scenario)
+	if err := eng.db.Set(ctx, key, state); err != nil {
+		return errors.Wrap(err, "set scenario state")
+	}
+	return nil
+}
+
+// activeSession returns the active session of the mock, the requests are matched without session if it is unknown
+func (eng *Engine) activeSession(ctx context.Context) string {
+	sessionID, err := eng.db.GetActiveSession(ctx, eng.mockID)
+	if err != nil {
+		log.WithError(err).WithField("config_id", eng.mockID).Error("get active session")
+	}
+	return sessionID
+}
+
 func (eng *Engine) Handler(w http.ResponseWriter, r *http.Request) {
 	if eng.isPaused {
 		w.WriteHeader(http.StatusServiceUnavailable)
@@ -86,8 +132,13 @@ func (eng *Engine) Handler(w http.ResponseWriter, r *http.Request) {
 	if response == nil {
 		mok := eng.getMock()
 
-		if mok.AutoCORS && r.Method == http.MethodOptions {
-			eng.corsHandler(w, r)
+		if cors := mok.CORSPolicy(); cors != nil && r.Method == http.MethodOptions {
+			eng.corsHandler(w, r, cors)
+			return
+		}
+
+		if location, ok := eng.redirectPath(r); ok {
+			eng.redirectHandler(w, r, location)
 			return
 		}
 
@@ -96,23 +147,155 @@ func (eng *Engine) Handler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		eng.noMatchHandler(w)
+		if allowed := eng.allowedMethods(r); len(allowed) > 0 {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		eng.noMatchHandler(w, r)
 		return
 	}
 
-	for k, v := range response.Headers {
-		w.Header().Add(k, v)
+	body, err := eng.responseBody(r, response)
+	if err != nil {
+		log.WithError(err).Error("render response body")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	for k, values := range response.Headers {
+		for _, v := range values {
+			w.Header().Add(k, v)
+		}
+	}
+
+	// the headers of the response take precedence over the CORS policy
+	if cors := eng.getMock().CORSPolicy(); cors != nil && !cors.PreflightOnly {
+		for k, v := range cors.ResponseHeaders(r) {
+			if w.Header().Get(k) == "" {
+				w.Header()[k] = v
+			}
+		}
 	}
 
 	w.WriteHeader(response.Status)
-	_, _ = w.Write([]byte(response.Body))
+	// the HEAD requests answered by the GET routes have no body
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(body)
+	}
+}
+
+// templateRequest is the request the templated response bodies are rendered with
+type templateRequest struct {
+	Method  string
+	Path    string
+	Query   string
+	Headers http.Header
+	Body    string
+}
+
+type responseData struct {
+	Request templateRequest
+}
+
+// responseBody returns the body of the response, rendered with the request if it is a template
+func (eng *Engine) responseBody(r *http.Request, response *mock.Response) ([]byte, error) {
+	if !response.Template {
+		return []byte(response.Body), nil
+	}
+
+	body, err := eng.matchContext(r, eng.getMock()).Body()
+	if err != nil {
+		return nil, err
+	}
+
+	return response.RenderBody(responseData{Request: templateRequest{
+		Method:  r.Method,
+		Path:    r.URL.Path,
+		Query:   r.URL.RawQuery,
+		Headers: r.Header,
+		Body:    string(body),
+	}})
 }
 
-func (eng *Engine) noMatchHandler(w http.ResponseWriter) {
-	w.WriteHeader(http.StatusNotFound)
+// matchContext returns the context the routes of the mock are matched with
+func (eng *Engine) matchContext(r *http.Request, mok *mock.Mock) matcher.Context {
+	return matcher.Context{
+		HTTPRequest: r,
+		MockID:      eng.mockID,
+		Scenarios:   mok.Scenarios(),
+		MaxBodySize: eng.maxBodySize,
+		Namespaces:  mok.Namespaces,
+		BasePath:    mok.BasePath,
+		PathOptions: mok.PathOptions,
+	}
 }
 
-func (eng *Engine) corsHandler(w http.ResponseWriter, r *http.Request) {
+// redirectPath returns the path of the request with or without its trailing slash, when only that one matches a route
+func (eng *Engine) redirectPath(r *http.Request) (string, bool) {
+	mok := eng.getMock()
+	for _, route := range mok.Routes {
+		path, ok, err := matcher.NewRouteMatcher(route, eng.matchContext(r, mok), eng.db).RedirectPath()
+		if err != nil {
+			log.WithError(err).Error("matching route")
+			continue
+		}
+		if ok {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// redirectHandler redirects the request permanently, the other methods than GET keep their body
+func (eng *Engine) redirectHandler(w http.ResponseWriter, r *http.Request, path string) {
+	location := path
+	if r.URL.RawQuery != "" {
+		location += "?" + r.URL.RawQuery
+	}
+
+	status := http.StatusPermanentRedirect
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		status = http.StatusMovedPermanently
+	}
+
+	w.Header().Set("Location", location)
+	w.WriteHeader(status)
+}
+
+// allowedMethods returns the methods of the routes whose path matches the request, when none of them accepts its method
+func (eng *Engine) allowedMethods(r *http.Request) []string {
+	mok := eng.getMock()
+	methods := map[string]bool{}
+	for _, route := range mok.Routes {
+		matched, err := matcher.NewRouteMatcher(route, eng.matchContext(r, mok), eng.db).MatchPath()
+		if err != nil || !matched {
+			continue
+		}
+		if route.MatchesMethod(r.Method) {
+			return nil
+		}
+		for _, method := range route.AllowedMethods() {
+			methods[method] = true
+			if method == http.MethodGet {
+				methods[http.MethodHead] = true
+			}
+		}
+	}
+
+	allowed := make([]string, 0, len(methods))
+	for method := range methods {
+		allowed = append(allowed, method)
+	}
+	sort.Strings(allowed)
+	return allowed
+}
+
+func (eng *Engine) corsHandler(w http.ResponseWriter, r *http.Request, cors *mock.CORS) {
+	for k, v := range cors.PreflightHeaders(r) {
+		w.Header()[k] = v
+	}
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -122,14 +305,14 @@ func (eng *Engine) proxyHandler(w http.ResponseWriter, r *http.Request) {
 	req, err := copyProxyRequest(r, proxy)
 	if err != nil {
 		log.WithError(err).Error("copy request")
-		eng.noMatchHandler(w)
+		eng.noMatchHandler(w, r)
 		return
 	}
 
 	res, err := (&http.Client{}).Do(req)
 	if err != nil {
 		log.WithError(err).Error("make proxy request")
-		eng.noMatchHandler(w)
+		eng.noMatchHandler(w, r)
 		return
 	}
 	defer func() { _ = res.Body.Close() }()
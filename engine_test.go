@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -68,6 +69,124 @@ func TestEngine_Match(t *testing.T) {
 	assert.Equal(t, "test", res.Header.Get("X-Test"))
 }
 
+func TestEngine_Methods(t *testing.T) {
+	mem := memory.New()
+	_ = mem.SetMock(context.Background(), &mock.Mock{
+		ID: "mock-id",
+		Routes: []*mock.Route{
+			{Path: "/hello", Responses: []mock.Response{{Status: 200, Body: "Hello World"}}},
+			{Methods: []string{"PUT", "patch"}, Path: "/hello", Responses: []mock.Response{{Status: 204}}},
+			{Method: "ANY", Path: "/ping", Responses: []mock.Response{{Status: 200, Body: "pong"}}},
+			{Method: "POST", Path: "/orders", Responses: []mock.Response{{
+				Status: 201,
+				Rules:  []mock.Rule{{Target: mock.Header, Modifier: "Authorization", Operator: mock.Exists}},
+			}}},
+		},
+	})
+	eng := engine.New("mock-id", mem)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+		body   string
+		allow  string
+	}{
+		{"one of the methods", http.MethodPatch, "/hello", http.StatusNoContent, "", ""},
+		{"any method", http.MethodDelete, "/ping", http.StatusOK, "pong", ""},
+		{"head answered by the get route", http.MethodHead, "/hello", http.StatusOK, "", ""},
+		{"method not allowed", http.MethodDelete, "/hello", http.StatusMethodNotAllowed, "", "GET, HEAD, PATCH, PUT"},
+		{"rules not matched", http.MethodPost, "/orders", http.StatusNotFound, "", ""},
+		{"path not matched", http.MethodDelete, "/users", http.StatusNotFound, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			eng.Handler(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.body, w.Body.String())
+			assert.Equal(t, tt.allow, w.Header().Get("Allow"))
+		})
+	}
+}
+
+func TestEngine_TrailingSlashRedirect(t *testing.T) {
+	mem := memory.New()
+	_ = mem.SetMock(context.Background(), &mock.Mock{
+		ID:          "mock-id",
+		BasePath:    "/v1",
+		PathOptions: &mock.PathOptions{TrailingSlash: mock.RedirectTrailingSlash},
+		Routes: []*mock.Route{
+			{Path: "/users", Responses: []mock.Response{{Status: 200, Body: "users"}}},
+			{Method: "POST", Path: "/orders/", Responses: []mock.Response{{Status: 201}}},
+		},
+	})
+	eng := engine.New("mock-id", mem)
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		status   int
+		location string
+	}{
+		{"matched path", http.MethodGet, "/v1/users", http.StatusOK, ""},
+		{"get redirected", http.MethodGet, "/v1/users/?page=2", http.StatusMovedPermanently, "/v1/users?page=2"},
+		{"post redirected", http.MethodPost, "/v1/orders", http.StatusPermanentRedirect, "/v1/orders/"},
+		{"not under the base path", http.MethodGet, "/users", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			eng.Handler(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.location, w.Header().Get("Location"))
+		})
+	}
+}
+
+func TestEngine_Scenarios(t *testing.T) {
+	mem := memory.New()
+	_ = mem.SetMock(context.Background(), &mock.Mock{
+		ID: "mock-id",
+		Routes: []*mock.Route{
+			{Path: "/orders/1", Responses: []mock.Response{
+				{Status: 200, Body: "pending", Scenario: "order", RequiredState: mock.StartedState, NewState: "shipped"},
+				{Status: 200, Body: "shipped", Scenario: "order", RequiredState: "shipped", NewState: "delivered"},
+				{Status: 200, Body: "delivered", Scenario: "order", RequiredState: "delivered"},
+			}},
+		},
+	})
+	eng := engine.New("mock-id", mem)
+
+	get := func() string {
+		w := httptest.NewRecorder()
+		eng.Handler(w, httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+		return w.Body.String()
+	}
+
+	assert.Equal(t, "pending", get())
+	assert.Equal(t, "shipped", get())
+	assert.Equal(t, "delivered", get())
+	assert.Equal(t, "delivered", get())
+
+	require.NoError(t, eng.ResetScenarios(context.Background()))
+	assert.Equal(t, "pending", get())
+
+	require.NoError(t, eng.SetScenarioState(context.Background(), "order", "delivered"))
+	assert.Equal(t, "delivered", get())
+
+	t.Run("the states are kept by session", func(t *testing.T) {
+		require.NoError(t, mem.SetActiveSession(context.Background(), "mock-id", "new-session"))
+		assert.Equal(t, "pending", get())
+	})
+}
+
 func TestEngine_Match_With_Delay_Response(t *testing.T) {
 	mem := memory.New()
 	_ = mem.SetMock(context.Background(), &mock.Mock{
@@ -174,6 +293,125 @@ func TestEngine_ProxyHandler(t *testing.T) {
 	assert.Equal(t, "from response", res.Header.Get("X-Response"))
 }
 
+func TestEngine_ProxyHandler_Body(t *testing.T) {
+	proxyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	defer proxyServer.Close()
+
+	mok := &mock.Mock{
+		ID: "mock-id",
+		Routes: []*mock.Route{
+			{
+				Method: http.MethodPost,
+				Path:   "/orders",
+				Responses: []mock.Response{
+					{
+						Status: http.StatusCreated,
+						Rules: []mock.Rule{
+							{Target: mock.Body, Modifier: ".quantity", Value: "10", Operator: mock.GreaterThan},
+						},
+					},
+				},
+			},
+		},
+		Proxy: &mock.Proxy{Enabled: true, Host: proxyServer.URL},
+	}
+	mem := memory.New()
+	_ = mem.SetMock(context.Background(), mok)
+
+	t.Run("the body read by the rules is forwarded", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		engine.New("mock-id", mem).Handler(w, httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"quantity": 2}`)))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"quantity": 2}`, w.Body.String())
+	})
+
+	t.Run("the body larger than the limit is not matched, and is forwarded", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		engine.New("mock-id", mem, engine.WithMaxBodySize(8)).Handler(w, httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"quantity": 20}`)))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"quantity": 20}`, w.Body.String())
+	})
+
+	t.Run("the body matches", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		engine.New("mock-id", mem).Handler(w, httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"quantity": 20}`)))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+}
+
+func TestEngine_TemplatedResponse(t *testing.T) {
+	mok := &mock.Mock{
+		ID: "mock-id",
+		Routes: []*mock.Route{
+			{
+				Method: http.MethodPost,
+				Path:   "/stock",
+				Responses: []mock.Response{
+					{
+						Status:   http.StatusOK,
+						Headers:  mock.Headers{"Content-Type": {"text/xml"}},
+						Template: true,
+						Body: `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <GetStockResponse customer="{{ xml (.Request.Headers.Get "X-Customer") }}">
+      <Query>{{ xml .Request.Query }}</Query>
+      <Length>{{ len .Request.Body }}</Length>
+    </GetStockResponse>
+  </soap:Body>
+</soap:Envelope>`,
+						Rules: []mock.Rule{
+							{Target: mock.SOAPAction, Value: "GetStock", Operator: mock.Equal},
+						},
+					},
+				},
+			},
+			{
+				Method: http.MethodGet,
+				Path:   "/broken",
+				Responses: []mock.Response{
+					{Status: http.StatusOK, Template: true, Body: "{{ .Missing }}"},
+				},
+			},
+		},
+	}
+	mem := memory.New()
+	_ = mem.SetMock(context.Background(), mok)
+	eng := engine.New("mock-id", mem)
+
+	t.Run("the values of the request are escaped in the SOAP envelope", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/stock?sku=a&b", strings.NewReader(`<soap:Envelope/>`))
+		req.Header.Set("SOAPAction", "GetStock")
+		req.Header.Set("X-Customer", `Tom & "Jerry" <tj>`)
+		w := httptest.NewRecorder()
+		eng.Handler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/xml", w.Header().Get("Content-Type"))
+		assert.Equal(t, `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <GetStockResponse customer="Tom &amp; &#34;Jerry&#34; &lt;tj&gt;">
+      <Query>sku=a&amp;b</Query>
+      <Length>16</Length>
+    </GetStockResponse>
+  </soap:Body>
+</soap:Envelope>`, w.Body.String())
+	})
+
+	t.Run("a template failing to render is an internal error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		eng.Handler(w, httptest.NewRequest(http.MethodGet, "/broken", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+}
+
 func TestEngine_CORS_Request(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -233,6 +471,18 @@ func TestEngine_CORS_Request(t *testing.T) {
 	}
 }
 
+func TestEngine_AutoCORS_PreflightOnly(t *testing.T) {
+	eng := engine.New("mock-id", setupMock())
+
+	req := httptest.NewRequest(http.MethodGet, "/hello", nil)
+	req.Header.Set("Origin", "https://example.com")
+	w := httptest.NewRecorder()
+	eng.Handler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
+
 func TestEngine_MockNotFound(t *testing.T) {
 	mem := memory.New()
 	_ = mem.SetMock(context.Background(), &mock.Mock{
@@ -256,9 +506,9 @@ func setupMock() persistent.Persistent {
 					{
 						Status: 200,
 						Body:   "Hello World",
-						Headers: map[string]string{
-							"Content-Type": "text/plain",
-							"X-Test":       "test",
+						Headers: map[string][]string{
+							"Content-Type": {"text/plain"},
+							"X-Test":       {"test"},
 						},
 					},
 				},
@@ -270,3 +520,146 @@ func setupMock() persistent.Persistent {
 
 	return mem
 }
+
+func TestEngine_Fallback(t *testing.T) {
+	routes := []*mock.Route{
+		{Method: "GET", Path: "/users/:id", Responses: []mock.Response{{Status: 200}}},
+		{Method: "POST", Path: "/users", Responses: []mock.Response{{Status: 201}}},
+		{Method: "GET", Path: "/orders", Responses: []mock.Response{{Status: 200}}},
+		{Method: "GET", Path: "/health", Responses: []mock.Response{{Status: 200}}},
+	}
+
+	tests := []struct {
+		name           string
+		fallback       *mock.Fallback
+		expectedStatus int
+		expectedHeader string
+		expectedBody   string
+	}{
+		{
+			"no fallback, expect empty 404",
+			nil,
+			http.StatusNotFound,
+			"",
+			"",
+		},
+		{
+			"static fallback",
+			&mock.Fallback{
+				Status:  http.StatusTeapot,
+				Headers: mock.Headers{"Content-Type": {"text/plain"}},
+				Body:    "no mock configured",
+			},
+			http.StatusTeapot,
+			"text/plain",
+			"no mock configured",
+		},
+		{
+			"templated fallback",
+			&mock.Fallback{
+				Body:     `{{ .Request.Method }} {{ .Request.Path }} {{ json .ClosestRoutes }}`,
+				Template: true,
+			},
+			http.StatusNotFound,
+			"",
+			`GET /user/1/orders [{"method":"GET","path":"/users/:id"},{"method":"GET","path":"/orders"},{"method":"GET","path":"/health"}]`,
+		},
+		{
+			"strict fallback",
+			&mock.Fallback{
+				Status: http.StatusTeapot,
+				Strict: true,
+			},
+			http.StatusNotImplemented,
+			"application/json",
+			`{"error":"no route matches the request","method":"GET","path":"/user/1/orders","closest_routes":[{"method":"GET","path":"/users/:id"},{"method":"GET","path":"/orders"},{"method":"GET","path":"/health"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := memory.New()
+			_ = mem.SetMock(context.Background(), &mock.Mock{
+				ID:       "mock-id",
+				Routes:   routes,
+				Fallback: tt.fallback,
+			})
+			eng := engine.New("mock-id", mem)
+
+			w := httptest.NewRecorder()
+			eng.Handler(w, httptest.NewRequest(http.MethodGet, "/user/1/orders", nil))
+			res := w.Result()
+			body, _ := io.ReadAll(res.Body)
+			defer func() {
+				_ = res.Body.Close()
+			}()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+			assert.Equal(t, tt.expectedHeader, res.Header.Get("Content-Type"))
+			assert.Equal(t, tt.expectedBody, string(body))
+		})
+	}
+}
+
+func TestEngine_CORS_Policy(t *testing.T) {
+	mok := &mock.Mock{
+		ID: "mock-id",
+		CORS: &mock.CORS{
+			Enabled:          true,
+			AllowOrigins:     []string{"https://app.example.com"},
+			AllowCredentials: true,
+			ExposeHeaders:    []string{"X-Total-Count"},
+			MaxAge:           600,
+		},
+		Routes: []*mock.Route{
+			{
+				Method: "GET",
+				Path:   "/hello",
+				Responses: []mock.Response{
+					{Status: 200, Body: "Hello World"},
+				},
+			},
+		},
+	}
+
+	serve := func(method, origin string) *http.Response {
+		mem := memory.New()
+		_ = mem.SetMock(context.Background(), mok)
+		req := httptest.NewRequest(method, "/hello", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		req.Header.Set("Access-Control-Request-Headers", "Authorization")
+		w := httptest.NewRecorder()
+		engine.New("mock-id", mem).Handler(w, req)
+		return w.Result()
+	}
+
+	t.Run("preflight request", func(t *testing.T) {
+		res := serve(http.MethodOptions, "https://app.example.com")
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "https://app.example.com", res.Header.Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", res.Header.Get("Access-Control-Allow-Credentials"))
+		assert.Equal(t, "GET, HEAD, PUT, PATCH, POST, DELETE", res.Header.Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "Authorization", res.Header.Get("Access-Control-Allow-Headers"))
+		assert.Equal(t, "600", res.Header.Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("cross origin response", func(t *testing.T) {
+		res := serve(http.MethodGet, "https://app.example.com")
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "https://app.example.com", res.Header.Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "X-Total-Count", res.Header.Get("Access-Control-Expose-Headers"))
+		assert.Equal(t, "Origin", res.Header.Get("Vary"))
+	})
+
+	t.Run("origin is not allowed", func(t *testing.T) {
+		res := serve(http.MethodOptions, "https://evil.example.com")
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Empty(t, res.Header.Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, res.Header.Get("Access-Control-Allow-Methods"))
+	})
+}
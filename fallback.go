@@ -0,0 +1,180 @@
+package engine
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/mockingio/engine/mock"
+)
+
+const maxClosestRoutes = 3
+
+type closestRoute struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	Description string `json:"description,omitempty"`
+}
+
+type unmatchedRequest struct {
+	Method  string      `json:"method"`
+	Path    string      `json:"path"`
+	Query   string      `json:"query,omitempty"`
+	Headers http.Header `json:"-"`
+}
+
+type fallbackData struct {
+	Request       unmatchedRequest `json:"request"`
+	ClosestRoutes []closestRoute   `json:"closest_routes"`
+}
+
+type strictFallbackBody struct {
+	Error         string         `json:"error"`
+	Method        string         `json:"method"`
+	Path          string         `json:"path"`
+	ClosestRoutes []closestRoute `json:"closest_routes"`
+}
+
+func (eng *Engine) noMatchHandler(w http.ResponseWriter, r *http.Request) {
+	mok := eng.getMock()
+	if mok == nil || mok.Fallback == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	fallback := mok.Fallback
+	data := fallbackData{
+		Request: unmatchedRequest{
+			Method:  r.Method,
+			Path:    r.URL.Path,
+			Query:   r.URL.RawQuery,
+			Headers: r.Header,
+		},
+		ClosestRoutes: findClosestRoutes(mok.Routes, mok.BasePath, r.Method, r.URL.Path),
+	}
+
+	for k, values := range fallback.Headers {
+		for _, v := range values {
+			w.Header().Add(k, v)
+		}
+	}
+
+	if fallback.Strict {
+		body, err := json.Marshal(strictFallbackBody{
+			Error:         "no route matches the request",
+			Method:        data.Request.Method,
+			Path:          data.Request.Path,
+			ClosestRoutes: data.ClosestRoutes,
+		})
+		if err != nil {
+			log.WithError(err).Error("marshal strict fallback body")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(fallback.StatusCode())
+		_, _ = w.Write(body)
+		return
+	}
+
+	body, err := fallback.RenderBody(data)
+	if err != nil {
+		log.WithError(err).Error("render fallback body")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(fallback.StatusCode())
+	_, _ = w.Write(body)
+}
+
+// findClosestRoutes returns the routes whose path is the most similar to the requested one,
+// routes with the same method come first when they are equally similar. The paths of the routes are prefixed by the base path
+func findClosestRoutes(routes []*mock.Route, basePath, method, path string) []closestRoute {
+	basePath = strings.TrimSuffix(basePath, "/")
+
+	type scored struct {
+		route    *mock.Route
+		distance int
+		sameVerb bool
+	}
+
+	var candidates []scored
+	for _, route := range routes {
+		candidates = append(candidates, scored{
+			route:    route,
+			distance: pathDistance(basePath+route.Path, path),
+			sameVerb: route.MatchesMethod(method),
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].sameVerb && !candidates[j].sameVerb
+	})
+
+	closest := []closestRoute{}
+	for i, c := range candidates {
+		if i == maxClosestRoutes {
+			break
+		}
+		method := c.route.Method
+		if method == "" {
+			method = strings.Join(c.route.Methods, ", ")
+		}
+		closest = append(closest, closestRoute{
+			Method:      method,
+			Path:        basePath + c.route.Path,
+			Description: c.route.Description,
+		})
+	}
+
+	return closest
+}
+
+// pathDistance is the Levenshtein distance between the path segments,
+// parameters and wildcards in the route path are equal to any segment
+func pathDistance(routePath, path string) int {
+	a := strings.Split(strings.Trim(routePath, "/"), "/")
+	b := strings.Split(strings.Trim(path, "/"), "/")
+
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if segmentMatches(a[i-1], b[j-1]) {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+func segmentMatches(routeSegment, segment string) bool {
+	if strings.HasPrefix(routeSegment, ":") || strings.HasPrefix(routeSegment, "*") {
+		return true
+	}
+	return strings.EqualFold(routeSegment, segment)
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
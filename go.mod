@@ -3,22 +3,31 @@ module github.com/mockingio/engine
 go 1.18
 
 require (
+	github.com/antchfx/xmlquery v1.3.12
+	github.com/antchfx/xpath v1.2.1
 	github.com/go-ozzo/ozzo-validation/v4 v4.3.0
+	github.com/google/cel-go v0.12.5
 	github.com/google/uuid v1.3.0
 	github.com/itchyny/gojq v0.12.8
-	github.com/minio/pkg v1.2.0
 	github.com/pkg/errors v0.9.1
 	github.com/samber/lo v1.25.0
 	github.com/sirupsen/logrus v1.8.1
 	github.com/stretchr/testify v1.8.0
 	gopkg.in/yaml.v2 v2.4.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
+	github.com/antlr/antlr4/runtime/Go/antlr v0.0.0-20220418222510-f25a4f6275ed // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/golang/groupcache v0.0.0-20200121045136-8c9f03a8e57e // indirect
 	github.com/itchyny/timefmt-go v0.1.3 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/stoewer/go-strcase v1.2.0 // indirect
 	golang.org/x/exp v0.0.0-20220303212507-bbda1eaf7a17 // indirect
+	golang.org/x/net v0.0.0-20220127200216-cd36cc0744dd // indirect
 	golang.org/x/sys v0.0.0-20220520151302-bc2c85ada10a // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	golang.org/x/text v0.3.7 // indirect
+	google.golang.org/genproto v0.0.0-20220502173005-c8bf987b8c21 // indirect
+	google.golang.org/protobuf v1.28.0 // indirect
 )
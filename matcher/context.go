@@ -1,13 +1,37 @@
 package matcher
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+
+	"github.com/pkg/errors"
+
+	cfg "github.com/mockingio/engine/mock"
 )
 
+// DefaultMaxBodySize is the size limit of the request bodies read by the rules, 10 MiB
+const DefaultMaxBodySize int64 = 10 << 20
+
 type Context struct {
 	HTTPRequest *http.Request
 	SessionID   string
+	// MockID scopes the states of the scenarios to the mock
+	MockID string
+	// Scenarios are the names of the scenarios of the mock, the expressions read their states in the session
+	Scenarios []string
+	// MaxBodySize limits the size of the body read by the rules, DefaultMaxBodySize if it is not set
+	MaxBodySize int64
+	// Namespaces are the XML namespaces of the mock by their prefix
+	Namespaces map[string]string
+	// Params are the URL decoded values of the params of the path of the matched route
+	Params map[string]string
+	// BasePath prefixes the paths of the routes
+	BasePath string
+	// PathOptions control how the path of the request is normalized, the path is matched as it is if it is not set
+	PathOptions *cfg.PathOptions
 }
 
 func (r Context) CountID() string {
@@ -17,3 +41,50 @@ func (r Context) CountID() string {
 func (r Context) SequenceID() string {
 	return fmt.Sprintf("%s-%s-%s-sequence", r.HTTPRequest.Method, r.HTTPRequest.URL, r.SessionID)
 }
+
+// bufferedBody is a request body which was read by the rules, it is rewound for each reader
+type bufferedBody struct {
+	io.Reader
+	data []byte
+	// rest is the part of the body after the size limit, which is never read by the rules
+	rest io.ReadCloser
+}
+
+func (b *bufferedBody) Close() error {
+	return b.rest.Close()
+}
+
+// Body returns the body of the request. The body is read once, then it is put back,
+// so that the other rules, routes and the proxy read it again
+func (r Context) Body() ([]byte, error) {
+	httpRequest := r.HTTPRequest
+	if httpRequest.Body == nil || httpRequest.Body == http.NoBody {
+		return nil, nil
+	}
+
+	limit := r.MaxBodySize
+	if limit <= 0 {
+		limit = DefaultMaxBodySize
+	}
+
+	body, ok := httpRequest.Body.(*bufferedBody)
+	if !ok {
+		data, err := ioutil.ReadAll(io.LimitReader(httpRequest.Body, limit+1))
+		if err != nil {
+			return nil, errors.Wrap(err, "read request body")
+		}
+		body = &bufferedBody{data: data, rest: httpRequest.Body}
+	}
+
+	httpRequest.Body = &bufferedBody{
+		Reader: io.MultiReader(bytes.NewReader(body.data), body.rest),
+		data:   body.data,
+		rest:   body.rest,
+	}
+
+	if int64(len(body.data)) > limit {
+		return nil, errors.Errorf("request body is larger than %d bytes", limit)
+	}
+
+	return body.data, nil
+}
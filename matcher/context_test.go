@@ -0,0 +1,51 @@
+package matcher_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mockingio/engine/matcher"
+)
+
+func TestContext_Body(t *testing.T) {
+	t.Run("the body is read once and put back", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"id": 1}`))
+		ctx := matcher.Context{HTTPRequest: req}
+
+		for i := 0; i < 2; i++ {
+			body, err := ctx.Body()
+			require.NoError(t, err)
+			assert.Equal(t, `{"id": 1}`, string(body))
+		}
+
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Equal(t, `{"id": 1}`, string(body))
+		assert.NoError(t, req.Body.Close())
+	})
+
+	t.Run("no body", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/orders", nil)
+
+		body, err := matcher.Context{HTTPRequest: req}.Body()
+		require.NoError(t, err)
+		assert.Nil(t, body)
+	})
+
+	t.Run("the body is larger than the limit", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"id": 1234567890}`))
+		ctx := matcher.Context{HTTPRequest: req, MaxBodySize: 8}
+
+		_, err := ctx.Body()
+		assert.EqualError(t, err, "request body is larger than 8 bytes")
+
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Equal(t, `{"id": 1234567890}`, string(body), "the whole body can still be read, by the proxy")
+	})
+}
@@ -0,0 +1,113 @@
+package matcher
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	cfg "github.com/mockingio/engine/mock"
+	"github.com/mockingio/engine/persistent"
+)
+
+// matchExpression evaluates the expression of the rule against the request. An evaluation error,
+// like a missing key of a map, does not match
+func (r *RuleMatcher) matchExpression() (bool, error) {
+	program, err := r.rule.Program()
+	if err != nil {
+		return false, errors.Wrap(err, "compile expression")
+	}
+
+	variables, err := expressionVariables(r.route, r.req, r.db)
+	if err != nil {
+		return false, err
+	}
+
+	out, _, err := program.Eval(variables)
+	if err != nil {
+		log.WithError(err).WithField("expression", r.rule.Value).Warn("evaluate expression, the rule does not match")
+		return false, nil
+	}
+
+	matched, ok := out.Value().(bool)
+	if !ok {
+		return false, errors.Errorf("expression %q returned %v, not a bool", r.rule.Value, out.Value())
+	}
+
+	return matched, nil
+}
+
+// expressionVariables returns the variables of the request, as declared by cfg.ExpressionVariables
+func expressionVariables(route *cfg.Route, req Context, db persistent.Persistent) (map[string]interface{}, error) {
+	httpRequest := req.HTTPRequest
+
+	requestNumber, err := db.GetInt(httpRequest.Context(), req.CountID())
+	if err != nil {
+		return nil, errors.Wrap(err, "get request number")
+	}
+
+	body, err := expressionBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	query := map[string]string{}
+	for _, param := range queryParams(httpRequest.URL.RawQuery) {
+		if _, ok := query[param.name]; !ok {
+			query[param.name] = param.value
+		}
+	}
+
+	// the headers can be read by their canonical or their lower case name, headers["X-Id"] or headers["x-id"]
+	headers := map[string]string{}
+	for name, values := range httpRequest.Header {
+		headers[http.CanonicalHeaderKey(name)] = strings.Join(values, ", ")
+		headers[strings.ToLower(name)] = strings.Join(values, ", ")
+	}
+
+	cookies := map[string]string{}
+	for _, cookie := range httpRequest.Cookies() {
+		cookies[cookie.Name] = cookie.Value
+	}
+
+	scenarios := map[string]string{}
+	for _, scenario := range req.Scenarios {
+		if scenarios[scenario], err = scenarioState(req, db, scenario); err != nil {
+			return nil, err
+		}
+	}
+
+	params := routeParams(route, req)
+	if params == nil {
+		params = map[string]string{}
+	}
+
+	return map[string]interface{}{
+		"method":         httpRequest.Method,
+		"path":           httpRequest.URL.Path,
+		"params":         params,
+		"query":          query,
+		"headers":        headers,
+		"cookies":        cookies,
+		"body":           body,
+		"request_number": int64(requestNumber),
+		"session":        map[string]interface{}{"id": req.SessionID, "scenarios": scenarios},
+	}, nil
+}
+
+// expressionBody returns the parsed JSON body, or the body as a string if it is not JSON
+func expressionBody(req Context) (interface{}, error) {
+	data, err := req.Body()
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+
+	var body interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return string(data), nil
+	}
+
+	return body, nil
+}
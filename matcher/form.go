@@ -0,0 +1,154 @@
+package matcher
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	cfg "github.com/mockingio/engine/mock"
+	"github.com/mockingio/engine/persistent"
+)
+
+type multipartFile struct {
+	name        string
+	contentType string
+	size        int64
+}
+
+// multipartForm is a multipart/form-data body, the content of the files is not kept
+type multipartForm struct {
+	values url.Values
+	files  map[string][]multipartFile
+}
+
+// mediaType returns the media type of the request body, and its params like the multipart boundary
+func mediaType(req Context) (string, map[string]string) {
+	mediaType, params, err := mime.ParseMediaType(req.HTTPRequest.Header.Get("Content-Type"))
+	if err != nil {
+		return "", nil
+	}
+	return mediaType, params
+}
+
+func getFormField(_ *cfg.Route, modifier string, req Context, _ persistent.Persistent) (string, bool, error) {
+	if mediaType, _ := mediaType(req); mediaType != "application/x-www-form-urlencoded" {
+		return "", false, nil
+	}
+
+	body, err := req.Body()
+	if err != nil {
+		return "", false, err
+	}
+
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return "", false, nil
+	}
+
+	return first(values[modifier])
+}
+
+func getMultipartField(_ *cfg.Route, modifier string, req Context, _ persistent.Persistent) (string, bool, error) {
+	form, err := parseMultipart(req)
+	if err != nil || form == nil {
+		return "", false, err
+	}
+
+	return first(form.values[modifier])
+}
+
+func getMultipartFileName(_ *cfg.Route, modifier string, req Context, _ persistent.Persistent) (string, bool, error) {
+	return multipartFileValue(req, modifier, func(file multipartFile) string {
+		return file.name
+	})
+}
+
+func getMultipartFileContentType(_ *cfg.Route, modifier string, req Context, _ persistent.Persistent) (string, bool, error) {
+	return multipartFileValue(req, modifier, func(file multipartFile) string {
+		return file.contentType
+	})
+}
+
+func getMultipartFileSize(_ *cfg.Route, modifier string, req Context, _ persistent.Persistent) (string, bool, error) {
+	return multipartFileValue(req, modifier, func(file multipartFile) string {
+		return strconv.FormatInt(file.size, 10)
+	})
+}
+
+// multipartFileValue returns the value of the first file uploaded in the field
+func multipartFileValue(req Context, field string, value func(file multipartFile) string) (string, bool, error) {
+	form, err := parseMultipart(req)
+	if err != nil || form == nil {
+		return "", false, err
+	}
+
+	files := form.files[field]
+	if len(files) == 0 {
+		return "", false, nil
+	}
+
+	return value(files[0]), true, nil
+}
+
+// parseMultipart parses the buffered body, the form is nil if the body is not multipart/form-data
+func parseMultipart(req Context) (*multipartForm, error) {
+	mediaType, params := mediaType(req)
+	if mediaType != "multipart/form-data" || params["boundary"] == "" {
+		return nil, nil
+	}
+
+	body, err := req.Body()
+	if err != nil {
+		return nil, err
+	}
+
+	form := &multipartForm{values: url.Values{}, files: map[string][]multipartFile{}}
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return form, nil
+		}
+		if err != nil {
+			// a malformed body has no field
+			return nil, nil
+		}
+
+		name := part.FormName()
+		if name == "" {
+			continue
+		}
+
+		if part.FileName() == "" {
+			value, err := ioutil.ReadAll(part)
+			if err != nil {
+				return nil, errors.Wrap(err, "read multipart field")
+			}
+			form.values.Add(name, string(value))
+			continue
+		}
+
+		size, err := io.Copy(ioutil.Discard, part)
+		if err != nil {
+			return nil, errors.Wrap(err, "read multipart file")
+		}
+		form.files[name] = append(form.files[name], multipartFile{
+			name:        part.FileName(),
+			contentType: part.Header.Get("Content-Type"),
+			size:        size,
+		})
+	}
+}
+
+func first(values []string) (string, bool, error) {
+	if len(values) == 0 {
+		return "", false, nil
+	}
+	return values[0], true, nil
+}
@@ -0,0 +1,88 @@
+package matcher_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mockingio/engine/matcher"
+	cfg "github.com/mockingio/engine/mock"
+	"github.com/mockingio/engine/persistent/memory"
+)
+
+func TestRuleMatcher_Match_Form(t *testing.T) {
+	newFormRequest := func() *http.Request {
+		req, _ := http.NewRequest(http.MethodPost, "/login", strings.NewReader("user=joe&remember=on&tags=a&tags=b"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req
+	}
+
+	newMultipartRequest := func() *http.Request {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		_ = writer.WriteField("title", "Holidays")
+
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", `form-data; name="photo"; filename="beach.png"`)
+		header.Set("Content-Type", "image/png")
+		part, _ := writer.CreatePart(header)
+		_, _ = part.Write(bytes.Repeat([]byte{0x89}, 2048))
+		_ = writer.Close()
+
+		req, _ := http.NewRequest(http.MethodPost, "/photos", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		return req
+	}
+
+	tests := []struct {
+		name    string
+		request *http.Request
+		rule    *cfg.Rule
+		matched bool
+	}{
+		{"form field", newFormRequest(), &cfg.Rule{Target: cfg.FormField, Modifier: "user", Value: "joe", Operator: cfg.Equal}, true},
+		{"first value of the form field", newFormRequest(), &cfg.Rule{Target: cfg.FormField, Modifier: "tags", Value: "a", Operator: cfg.Equal}, true},
+		{"missing form field", newFormRequest(), &cfg.Rule{Target: cfg.FormField, Modifier: "password", Operator: cfg.Absent}, true},
+		{"form field of a multipart body", newMultipartRequest(), &cfg.Rule{Target: cfg.FormField, Modifier: "title", Operator: cfg.Exists}, false},
+		{"multipart field", newMultipartRequest(), &cfg.Rule{Target: cfg.MultipartField, Modifier: "title", Value: "Holidays", Operator: cfg.Equal}, true},
+		{"multipart field of a form body", newFormRequest(), &cfg.Rule{Target: cfg.MultipartField, Modifier: "user", Operator: cfg.Exists}, false},
+		{"multipart file name", newMultipartRequest(), &cfg.Rule{Target: cfg.MultipartFileName, Modifier: "photo", Value: ".png", Operator: cfg.EndsWith}, true},
+		{"multipart file content type", newMultipartRequest(), &cfg.Rule{Target: cfg.MultipartFileContentType, Modifier: "photo", Value: "image/png", Operator: cfg.Equal}, true},
+		{"multipart file size", newMultipartRequest(), &cfg.Rule{Target: cfg.MultipartFileSize, Modifier: "photo", Value: "1024", Operator: cfg.GreaterThan}, true},
+		{"missing multipart file", newMultipartRequest(), &cfg.Rule{Target: cfg.MultipartFileName, Modifier: "title", Operator: cfg.Absent}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, err := matcher.NewRuleMatcher(&cfg.Route{}, tt.rule, matcher.Context{HTTPRequest: tt.request}, memory.New()).Match()
+			require.NoError(t, err)
+			assert.Equal(t, tt.matched, matched)
+		})
+	}
+
+	t.Run("the body can be read after the multipart rules", func(t *testing.T) {
+		req := newMultipartRequest()
+		ctx := matcher.Context{HTTPRequest: req}
+
+		for _, rule := range []*cfg.Rule{
+			{Target: cfg.MultipartFileSize, Modifier: "photo", Value: "2048", Operator: cfg.Equal},
+			{Target: cfg.MultipartField, Modifier: "title", Value: "Holidays", Operator: cfg.Equal},
+			{Target: cfg.Body, Value: "beach.png", Operator: cfg.Contains},
+		} {
+			matched, err := matcher.NewRuleMatcher(&cfg.Route{}, rule, ctx, memory.New()).Match()
+			require.NoError(t, err)
+			assert.True(t, matched, rule.Target)
+		}
+
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), "Holidays")
+	})
+}
@@ -0,0 +1,172 @@
+package matcher
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/itchyny/gojq"
+	"github.com/pkg/errors"
+
+	cfg "github.com/mockingio/engine/mock"
+	"github.com/mockingio/engine/persistent"
+)
+
+const (
+	anyPlaceholder   = "${any}"
+	regexPlaceholder = "${regex:"
+)
+
+// getValueFromJSONBody returns the JSON body, or the result of the jq query of the modifier encoded as JSON.
+// A body which is not JSON is absent
+func getValueFromJSONBody(_ *cfg.Route, modifier string, req Context, _ persistent.Persistent) (string, bool, error) {
+	body, err := req.Body()
+	if err != nil {
+		return "", false, err
+	}
+
+	var input interface{}
+	if err := json.Unmarshal(body, &input); err != nil {
+		return "", false, nil
+	}
+
+	if modifier == "" {
+		return string(body), true, nil
+	}
+
+	query, err := gojq.Parse(modifier)
+	if err != nil {
+		return "", false, nil
+	}
+
+	v, ok := query.Run(input).Next()
+	if !ok || v == nil {
+		return "", false, nil
+	}
+
+	if err, ok := v.(error); ok {
+		return "", false, errors.Wrapf(err, "unable to parse json query: %v", modifier)
+	}
+
+	result, err := json.Marshal(v)
+	if err != nil {
+		return "", false, errors.Wrap(err, "marshal json query result")
+	}
+
+	return string(result), true, nil
+}
+
+// matchJSON compares the JSON documents of the target and of the rule, a target which is not JSON does not match
+func matchJSON(operator cfg.Operator, value, ruleValue string, present bool) (bool, error) {
+	var expected interface{}
+	if err := json.Unmarshal([]byte(ruleValue), &expected); err != nil {
+		return false, errors.Wrapf(err, "parse %s value", operator)
+	}
+
+	var actual interface{}
+	if err := json.Unmarshal([]byte(value), &actual); err != nil || !present {
+		return false, nil
+	}
+
+	return jsonMatch(expected, actual, operator == cfg.JSONContains)
+}
+
+// jsonMatch returns true if the actual value is the expected value, or contains it if subset is true.
+// In a subset, the objects can have more keys, and the arrays more items in any order
+func jsonMatch(expected, actual interface{}, subset bool) (bool, error) {
+	if text, ok := expected.(string); ok {
+		if matched, ok, err := matchPlaceholder(text, actual); ok {
+			return matched, err
+		}
+	}
+
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok || (!subset && len(a) != len(e)) {
+			return false, nil
+		}
+		for key, value := range e {
+			item, ok := a[key]
+			if !ok {
+				return false, nil
+			}
+			if matched, err := jsonMatch(value, item, subset); err != nil || !matched {
+				return false, err
+			}
+		}
+		return true, nil
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok {
+			return false, nil
+		}
+		if subset {
+			return containsItems(e, a, make([]bool, len(a)))
+		}
+		if len(a) != len(e) {
+			return false, nil
+		}
+		for i := range e {
+			if matched, err := jsonMatch(e[i], a[i], subset); err != nil || !matched {
+				return false, err
+			}
+		}
+		return true, nil
+	default:
+		return reflect.DeepEqual(expected, actual), nil
+	}
+}
+
+// containsItems returns true if each expected item matches a different actual item, the items which are used are skipped
+func containsItems(expected, actual []interface{}, used []bool) (bool, error) {
+	if len(expected) == 0 {
+		return true, nil
+	}
+
+	for i, item := range actual {
+		if used[i] {
+			continue
+		}
+		matched, err := jsonMatch(expected[0], item, true)
+		if err != nil {
+			return false, err
+		}
+		if !matched {
+			continue
+		}
+
+		used[i] = true
+		if matched, err := containsItems(expected[1:], actual, used); err != nil || matched {
+			return matched, err
+		}
+		used[i] = false
+	}
+
+	return false, nil
+}
+
+// matchPlaceholder matches the ${any} and ${regex:pattern} placeholders, ok is false if the text is not a placeholder
+func matchPlaceholder(text string, actual interface{}) (matched bool, ok bool, err error) {
+	if text == anyPlaceholder {
+		return true, true, nil
+	}
+
+	if !strings.HasPrefix(text, regexPlaceholder) || !strings.HasSuffix(text, "}") {
+		return false, false, nil
+	}
+
+	value, isString := actual.(string)
+	if !isString {
+		return false, true, nil
+	}
+
+	pattern := text[len(regexPlaceholder) : len(text)-1]
+	matched, err = regexp.MatchString("^(?:"+pattern+")$", value)
+	if err != nil {
+		return false, true, errors.Wrap(err, "regex placeholder match string")
+	}
+
+	return matched, true, nil
+}
@@ -0,0 +1,58 @@
+package matcher_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mockingio/engine/matcher"
+	cfg "github.com/mockingio/engine/mock"
+	"github.com/mockingio/engine/persistent/memory"
+)
+
+func TestRuleMatcher_Match_JSON(t *testing.T) {
+	body := `{
+		"id": "ord-42",
+		"total": 12.5,
+		"customer": {"name": "Ann", "vip": true},
+		"items": [{"sku": "A-1", "qty": 2}, {"sku": "B-2", "qty": 1}],
+		"tags": ["new", "gift"]
+	}`
+
+	tests := []struct {
+		name    string
+		body    string
+		rule    *cfg.Rule
+		matched bool
+	}{
+		{"equal whatever the order of the keys", body, &cfg.Rule{Target: cfg.JSONBody, Operator: cfg.JSONEquals, Value: `{"tags": ["new", "gift"], "items": [{"qty": 2, "sku": "A-1"}, {"sku": "B-2", "qty": 1}], "customer": {"vip": true, "name": "Ann"}, "total": 12.50, "id": "ord-42"}`}, true},
+		{"not equal with a missing key", body, &cfg.Rule{Target: cfg.JSONBody, Operator: cfg.JSONEquals, Value: `{"id": "ord-42"}`}, false},
+		{"not equal with the items in another order", body, &cfg.Rule{Target: cfg.JSONBody, Modifier: ".tags", Operator: cfg.JSONEquals, Value: `["gift", "new"]`}, false},
+		{"equal query result", body, &cfg.Rule{Target: cfg.JSONBody, Modifier: ".customer", Operator: cfg.JSONEquals, Value: `{"name": "Ann", "vip": true}`}, true},
+		{"any placeholder", body, &cfg.Rule{Target: cfg.JSONBody, Modifier: ".customer", Operator: cfg.JSONEquals, Value: `{"name": "${any}", "vip": "${any}"}`}, true},
+		{"regex placeholder", body, &cfg.Rule{Target: cfg.JSONBody, Operator: cfg.JSONContains, Value: `{"id": "${regex:ord-[0-9]+}"}`}, true},
+		{"regex placeholder matches the entire string", body, &cfg.Rule{Target: cfg.JSONBody, Operator: cfg.JSONContains, Value: `{"id": "${regex:[0-9]+}"}`}, false},
+		{"regex placeholder does not match numbers", body, &cfg.Rule{Target: cfg.JSONBody, Operator: cfg.JSONContains, Value: `{"total": "${regex:.*}"}`}, false},
+		{"contains a subset", body, &cfg.Rule{Target: cfg.JSONBody, Operator: cfg.JSONContains, Value: `{"customer": {"vip": true}, "items": [{"sku": "B-2"}]}`}, true},
+		{"contains the items in any order", body, &cfg.Rule{Target: cfg.JSONBody, Operator: cfg.JSONContains, Value: `{"tags": ["gift", "new"]}`}, true},
+		{"contains each item once", body, &cfg.Rule{Target: cfg.JSONBody, Operator: cfg.JSONContains, Value: `{"tags": ["new", "new"]}`}, false},
+		{"contains items matching several items", body, &cfg.Rule{Target: cfg.JSONBody, Operator: cfg.JSONContains, Value: `{"items": [{"sku": "${any}"}, {"sku": "A-1"}]}`}, true},
+		{"does not contain another value", body, &cfg.Rule{Target: cfg.JSONBody, Operator: cfg.JSONContains, Value: `{"customer": {"vip": false}}`}, false},
+		{"does not contain another type", body, &cfg.Rule{Target: cfg.JSONBody, Operator: cfg.JSONContains, Value: `{"total": "12.5"}`}, false},
+		{"json operator on the body target", body, &cfg.Rule{Target: cfg.Body, Modifier: ".items[0]", Operator: cfg.JSONEquals, Value: `{"sku": "A-1", "qty": 2}`}, true},
+		{"body which is not json", "id=ord-42", &cfg.Rule{Target: cfg.JSONBody, Operator: cfg.Absent}, true},
+		{"missing query result", body, &cfg.Rule{Target: cfg.JSONBody, Modifier: ".shipping", Operator: cfg.JSONEquals, Value: `null`}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, "/orders", strings.NewReader(tt.body))
+			matched, err := matcher.NewRuleMatcher(&cfg.Route{}, tt.rule, matcher.Context{HTTPRequest: req}, memory.New()).Match()
+			require.NoError(t, err)
+			assert.Equal(t, tt.matched, matched)
+		})
+	}
+}
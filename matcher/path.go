@@ -0,0 +1,108 @@
+package matcher
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	cfg "github.com/mockingio/engine/mock"
+	"github.com/mockingio/engine/pathpattern"
+)
+
+var duplicateSlashesRegex = regexp.MustCompile(`//+`)
+
+func (r Context) pathOptions() cfg.PathOptions {
+	if r.PathOptions == nil {
+		return cfg.PathOptions{}
+	}
+	return *r.PathOptions
+}
+
+// routePath returns the escaped path of the request normalized by the path options, without the base path.
+// ok is false if the path is not under the base path
+func (r Context) routePath() (path string, ok bool) {
+	options := r.pathOptions()
+	path = r.HTTPRequest.URL.EscapedPath()
+
+	if options.MergeSlashes {
+		path = duplicateSlashesRegex.ReplaceAllString(path, "/")
+	}
+
+	if options.DecodePath {
+		// the segments are escaped again, so that the encoded slashes which were decoded separate the segments
+		segments := strings.Split(unescapePath(path), "/")
+		for i, segment := range segments {
+			segments[i] = url.PathEscape(segment)
+		}
+		path = strings.Join(segments, "/")
+	}
+
+	base := strings.TrimSuffix(r.BasePath, "/")
+	if base == "" {
+		return path, true
+	}
+
+	prefix := path
+	if len(prefix) > len(base) {
+		prefix = prefix[:len(base)]
+	}
+	if prefix != base && !(options.CaseInsensitive && strings.EqualFold(prefix, base)) {
+		return "", false
+	}
+
+	path = path[len(prefix):]
+	switch {
+	case path == "":
+		return "/", true
+	case strings.HasPrefix(path, "/"):
+		return path, true
+	default:
+		// the base path /v1 does not prefix /v10
+		return "", false
+	}
+}
+
+// matchRoutePath returns the params of the route path if the path of the request matches it
+func matchRoutePath(route *cfg.Route, req Context) (map[string]string, bool, error) {
+	pattern, err := route.CompilePath()
+	if err != nil {
+		return nil, false, errors.Wrap(err, "compile route path")
+	}
+
+	path, ok := req.routePath()
+	if !ok {
+		return nil, false, nil
+	}
+
+	options := req.pathOptions()
+	params, ok := matchPattern(pattern, path, options.CaseInsensitive)
+	if !ok && options.TrailingSlash == cfg.IgnoreTrailingSlash && path != "/" {
+		params, ok = matchPattern(pattern, toggleTrailingSlash(path), options.CaseInsensitive)
+	}
+
+	return params, ok, nil
+}
+
+func matchPattern(pattern *pathpattern.Pattern, path string, ignoreCase bool) (map[string]string, bool) {
+	if ignoreCase {
+		return pattern.MatchIgnoreCase(path)
+	}
+	return pattern.Match(path)
+}
+
+// toggleTrailingSlash removes the trailing slash of the path, or adds one
+func toggleTrailingSlash(path string) string {
+	if strings.HasSuffix(path, "/") {
+		return strings.TrimSuffix(path, "/")
+	}
+	return path + "/"
+}
+
+func unescapePath(path string) string {
+	if unescaped, err := url.PathUnescape(path); err == nil {
+		return unescaped
+	}
+	return path
+}
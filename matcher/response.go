@@ -29,34 +29,63 @@ type ResponseMatcher struct {
 }
 
 func (r *ResponseMatcher) Match() (bool, error) {
-	if len(r.response.Rules) == 0 {
+	if r.response.RequiredState != "" {
+		state, err := scenarioState(r.req, r.db, r.response.Scenario)
+		if err != nil || state != r.response.RequiredState {
+			return false, err
+		}
+	}
+
+	return r.matchGroup(r.response.RuleGroup())
+}
+
+// matchGroup matches the rules then the nested groups, it stops as soon as the result of the group is known
+func (r *ResponseMatcher) matchGroup(group cfg.RuleGroup) (bool, error) {
+	if group.IsEmpty() {
 		return true, nil
 	}
 
-	aggregation := r.response.RuleAggregation
+	aggregation := group.RuleAggregation
 	if aggregation == "" {
 		aggregation = cfg.And
 	}
 
-	for _, rule := range r.response.Rules {
+	for _, rule := range group.Rules {
 		matched, err := NewRuleMatcher(r.route, &rule, r.req, r.db).Match() // matcher. rule.Match(route, request)
 		if err != nil {
 			return false, errors.Wrap(err, "matching rule")
 		}
 
-		if !matched && aggregation == cfg.And {
-			return false, nil
+		if result, done := aggregate(aggregation, matched); done {
+			return result, nil
+		}
+	}
+
+	for _, nested := range group.RuleGroups {
+		matched, err := r.matchGroup(nested)
+		if err != nil {
+			return false, err
 		}
 
-		if matched && aggregation == cfg.Or {
-			return true, nil
+		if result, done := aggregate(aggregation, matched); done {
+			return result, nil
 		}
 	}
 
-	// Match all rules
-	if aggregation == cfg.And {
-		return true, nil
+	// Match all rules for and, none of them for not
+	return aggregation != cfg.Or, nil
+}
+
+// aggregate returns the result of the group, and true if it is known from the result of one of its members
+func aggregate(aggregation cfg.RuleAggregation, matched bool) (bool, bool) {
+	switch {
+	case aggregation == cfg.And && !matched:
+		return false, true
+	case aggregation == cfg.Or && matched:
+		return true, true
+	case aggregation == cfg.Not && matched:
+		return false, true
 	}
 
-	return false, nil
+	return false, false
 }
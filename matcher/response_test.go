@@ -97,6 +97,115 @@ func TestResponseMatcher_Match(t *testing.T) {
 			},
 			isMatched: false,
 		},
+		{
+			name: "several body rules, the body is read once",
+			response: &cfg.Response{
+				Rules: []cfg.Rule{
+					{Target: cfg.Body, Modifier: ".name", Value: "Joe", Operator: cfg.Equal},
+					{Target: cfg.Body, Modifier: ".name", Value: "J", Operator: cfg.StartsWith},
+					{Target: cfg.Body, Value: "Joe", Operator: cfg.Contains},
+				},
+			},
+			isMatched: true,
+		},
+		{
+			name: "aggregation is Not, found response, no rule matched",
+			response: &cfg.Response{
+				RuleAggregation: cfg.Not,
+				Rules: []cfg.Rule{
+					{Target: cfg.Header, Modifier: "Authorization", Value: "random name", Operator: cfg.Equal},
+					{Target: cfg.Header, Modifier: "X-Random", Operator: cfg.Exists},
+				},
+			},
+			isMatched: true,
+		},
+		{
+			name: "aggregation is Not, found no response, one of rule matched",
+			response: &cfg.Response{
+				RuleAggregation: cfg.Not,
+				Rules: []cfg.Rule{
+					{Target: cfg.Header, Modifier: "Authorization", Value: "random name", Operator: cfg.Equal},
+					{Target: cfg.Header, Modifier: "Authorization", Operator: cfg.Exists},
+				},
+			},
+			isMatched: false,
+		},
+		{
+			name: "rule groups, (A and B) or C, found response, group matched",
+			response: &cfg.Response{
+				RuleAggregation: cfg.Or,
+				Rules: []cfg.Rule{
+					{Target: cfg.Header, Modifier: "X-Random", Operator: cfg.Exists},
+				},
+				RuleGroups: []cfg.RuleGroup{
+					{
+						RuleAggregation: cfg.And,
+						Rules: []cfg.Rule{
+							{Target: cfg.Header, Modifier: "Authorization", Value: "Bearer ", Operator: cfg.StartsWith},
+							{Target: cfg.Body, Modifier: ".name", Value: "Joe", Operator: cfg.Equal},
+						},
+					},
+				},
+			},
+			isMatched: true,
+		},
+		{
+			name: "rule groups, (A and B) or C, found no response, group not matched",
+			response: &cfg.Response{
+				RuleAggregation: cfg.Or,
+				Rules: []cfg.Rule{
+					{Target: cfg.Header, Modifier: "X-Random", Operator: cfg.Exists},
+				},
+				RuleGroups: []cfg.RuleGroup{
+					{
+						Rules: []cfg.Rule{
+							{Target: cfg.Header, Modifier: "Authorization", Value: "Bearer ", Operator: cfg.StartsWith},
+							{Target: cfg.Body, Modifier: ".name", Value: "random name", Operator: cfg.Equal},
+						},
+					},
+				},
+			},
+			isMatched: false,
+		},
+		{
+			name: "rule groups, nested not group, found response",
+			response: &cfg.Response{
+				Rules: []cfg.Rule{
+					{Target: cfg.Header, Modifier: "Authorization", Operator: cfg.Exists},
+				},
+				RuleGroups: []cfg.RuleGroup{
+					{
+						RuleAggregation: cfg.Not,
+						RuleGroups: []cfg.RuleGroup{
+							{
+								Rules: []cfg.Rule{
+									{Target: cfg.Header, Modifier: "Authorization", Value: "Bearer 456", Operator: cfg.Equal},
+								},
+							},
+						},
+					},
+				},
+			},
+			isMatched: true,
+		},
+		{
+			name: "rule groups, short circuit, the following rules and groups are not evaluated",
+			response: &cfg.Response{
+				RuleAggregation: cfg.Or,
+				Rules: []cfg.Rule{
+					{Target: cfg.Header, Modifier: "Authorization", Value: "Bearer 123", Operator: cfg.Equal},
+					{Target: cfg.Header, Modifier: "Authorization", Value: "Bearer 123", Operator: cfg.Operator("random")},
+				},
+				RuleGroups: []cfg.RuleGroup{
+					{
+						Rules: []cfg.Rule{
+							{Target: cfg.Header, Modifier: "Authorization", Value: "Bearer 123", Operator: cfg.Operator("random")},
+						},
+					},
+				},
+			},
+			isMatched: true,
+		},
 	}
 
 	for _, tt := range tests {
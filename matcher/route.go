@@ -2,12 +2,10 @@ package matcher
 
 import (
 	"math/rand"
-	"net/http"
-	"strings"
 	"time"
 
-	"github.com/minio/pkg/wildcard"
 	"github.com/pkg/errors"
+	"github.com/samber/lo"
 
 	cfg "github.com/mockingio/engine/mock"
 	"github.com/mockingio/engine/persistent"
@@ -29,21 +27,17 @@ func NewRouteMatcher(route *cfg.Route, req Context, db persistent.Persistent) *R
 
 func (r *RouteMatcher) Match() (*cfg.Response, error) {
 	httpRequest := r.req.HTTPRequest
-	method := r.route.Method
-	if r.route.Method == "" {
-		method = http.MethodGet
-	}
-
-	if !strings.EqualFold(method, httpRequest.Method) {
+	if !r.route.MatchesMethod(httpRequest.Method) {
 		return nil, nil
 	}
 
-	wildcardPath := toWildcardPath(r.route.Path)
-	if !wildcard.Match(wildcardPath, httpRequest.URL.Path) {
-		return nil, nil
+	params, ok, err := r.matchPath()
+	if err != nil || !ok {
+		return nil, err
 	}
+	r.req.Params = params
 
-	_, err := r.db.Increment(
+	_, err = r.db.Increment(
 		httpRequest.Context(),
 		r.req.CountID(),
 	)
@@ -56,7 +50,74 @@ func (r *RouteMatcher) Match() (*cfg.Response, error) {
 		return nil, errors.Wrap(err, "matching route")
 	}
 
-	return r.pickResponse(responses)
+	response, err := r.pickResponse(responses)
+	if err != nil || response == nil || response.NewState == "" {
+		return response, err
+	}
+
+	if err := r.db.Set(httpRequest.Context(), r.req.ScenarioID(response.Scenario), response.NewState); err != nil {
+		return nil, errors.Wrap(err, "set scenario state")
+	}
+	return response, nil
+}
+
+// MatchPath returns true if the path and the query params of the request match the route, whatever the method
+func (r *RouteMatcher) MatchPath() (bool, error) {
+	_, ok, err := r.matchPath()
+	return ok, err
+}
+
+// matchPath returns the params of the path if the path and the query params of the request match the route
+func (r *RouteMatcher) matchPath() (map[string]string, bool, error) {
+	params, ok, err := matchRoutePath(r.route, r.req)
+	if err != nil || !ok || !r.matchQuery() {
+		return nil, false, err
+	}
+	return params, true, nil
+}
+
+// RedirectPath returns the path the request is redirected to when the trailing slashes are redirected,
+// it is the path of the request with or without its trailing slash if only that one matches the route
+func (r *RouteMatcher) RedirectPath() (string, bool, error) {
+	options := r.req.pathOptions()
+	if options.TrailingSlash != cfg.RedirectTrailingSlash || !r.route.MatchesMethod(r.req.HTTPRequest.Method) {
+		return "", false, nil
+	}
+
+	pattern, err := r.route.CompilePath()
+	if err != nil {
+		return "", false, errors.Wrap(err, "compile route path")
+	}
+
+	path, ok := r.req.routePath()
+	if !ok || path == "/" || !r.matchQuery() {
+		return "", false, nil
+	}
+	if _, ok := matchPattern(pattern, path, options.CaseInsensitive); ok {
+		return "", false, nil
+	}
+	if _, ok := matchPattern(pattern, toggleTrailingSlash(path), options.CaseInsensitive); !ok {
+		return "", false, nil
+	}
+
+	return toggleTrailingSlash(r.req.HTTPRequest.URL.EscapedPath()), true, nil
+}
+
+// matchQuery returns true if the request has the query params of the route, one of the values of a param must match
+func (r *RouteMatcher) matchQuery() bool {
+	for name, expected := range r.route.Query {
+		values := getQueryValues(name, false, r.req)
+		if len(values) == 0 {
+			return false
+		}
+		if expected == "*" {
+			continue
+		}
+		if !lo.Contains(values, expected) {
+			return false
+		}
+	}
+	return true
 }
 
 func (r *RouteMatcher) pickResponse(responses []*cfg.Response) (*cfg.Response, error) {
@@ -116,14 +177,3 @@ func (r *RouteMatcher) findMatches() ([]*cfg.Response, error) {
 
 	return responses, nil
 }
-
-func toWildcardPath(path string) string {
-	parts := strings.Split(path, "/")
-	for i, part := range parts {
-		if part != "" && string(part[0]) == ":" {
-			parts[i] = "*"
-		}
-	}
-
-	return strings.Join(parts, "/")
-}
@@ -116,6 +116,27 @@ func TestRouteMatcher_Match(t *testing.T) {
 			&singleRuleResponse,
 			false,
 		},
+		{
+			"variable does not match several segments, no response returned",
+			httpPostReqWithHeaderBody,
+			&cfg.Route{Method: "POST", Path: "/how/:someVariable", Responses: []cfg.Response{singleRuleResponse}},
+			nil,
+			false,
+		},
+		{
+			"constrained variable not matched, no response returned",
+			httpPostReqWithHeaderBody,
+			&cfg.Route{Method: "POST", Path: `/how/are/:someVariable(\d+)`, Responses: []cfg.Response{singleRuleResponse}},
+			nil,
+			false,
+		},
+		{
+			"invalid path, error returned",
+			httpPostReqWithHeaderBody,
+			&cfg.Route{Method: "POST", Path: "/how/*rest/you", Responses: []cfg.Response{singleRuleResponse}},
+			nil,
+			true,
+		},
 		{
 			"multiple rule matched, response returned",
 			httpPostReqWithHeaderBody,
@@ -185,6 +206,123 @@ func TestRouteMatcher_Match(t *testing.T) {
 	}
 }
 
+func TestRouteMatcher_Match_Params(t *testing.T) {
+	response := cfg.Response{
+		Status:          http.StatusOK,
+		RuleAggregation: cfg.And,
+		Rules: []cfg.Rule{
+			{Target: cfg.RouteParam, Modifier: "name", Value: "Ann Lee", Operator: cfg.Equal},
+			{Target: cfg.Expression, Value: `params.rest == "photos/beach.png"`},
+		},
+	}
+	route := &cfg.Route{Method: "GET", Path: "/users/:name/files/*rest", Responses: []cfg.Response{response}}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com/users/Ann%20Lee/files/photos/beach.png", nil)
+	result, err := matcher.NewRouteMatcher(route, matcher.Context{HTTPRequest: req}, memory.New()).Match()
+	require.NoError(t, err)
+	assert.Equal(t, &response, result)
+}
+
+func TestRouteMatcher_Match_RegexAndQuery(t *testing.T) {
+	paramResponses := []cfg.Response{{
+		Status: http.StatusOK,
+		Rules:  []cfg.Rule{{Target: cfg.RouteParam, Modifier: "name", Value: "notes", Operator: cfg.Equal}},
+	}}
+	responses := []cfg.Response{{Status: http.StatusOK}}
+
+	tests := []struct {
+		name    string
+		url     string
+		route   *cfg.Route
+		matched bool
+	}{
+		{"regex path", "/files/notes.txt", &cfg.Route{Path: `/files/(?P<name>[a-z]+)\.txt`, PathMode: cfg.PathRegex, Responses: paramResponses}, true},
+		{"regex path not matched", "/files/notes.txt/1", &cfg.Route{Path: `/files/(?P<name>[a-z]+)\.txt`, PathMode: cfg.PathRegex, Responses: paramResponses}, false},
+		{"query param", "/api?action=getUser", &cfg.Route{Path: "/api", Query: map[string]string{"action": "getUser"}, Responses: responses}, true},
+		{"query param with another value", "/api?action=deleteUser", &cfg.Route{Path: "/api", Query: map[string]string{"action": "getUser"}, Responses: responses}, false},
+		{"query param with any value", "/api?action=deleteUser", &cfg.Route{Path: "/api", Query: map[string]string{"action": "*"}, Responses: responses}, true},
+		{"missing query param", "/api?name=notes", &cfg.Route{Path: "/api", Query: map[string]string{"action": "*"}, Responses: responses}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "https://example.com"+tt.url, nil)
+			result, err := matcher.NewRouteMatcher(tt.route, matcher.Context{HTTPRequest: req}, memory.New()).Match()
+			require.NoError(t, err)
+			assert.Equal(t, tt.matched, result != nil)
+		})
+	}
+}
+
+func TestRouteMatcher_Match_PathOptions(t *testing.T) {
+	responses := []cfg.Response{{
+		Status: http.StatusOK,
+		Rules:  []cfg.Rule{{Target: cfg.RouteParam, Modifier: "name", Value: "a/b", Operator: cfg.Equal}},
+	}}
+	route := &cfg.Route{Path: "/files/:name", Responses: responses}
+
+	tests := []struct {
+		name     string
+		url      string
+		basePath string
+		options  *cfg.PathOptions
+		matched  bool
+	}{
+		{"strict trailing slash", "/files/a%2Fb/", "", nil, false},
+		{"ignored trailing slash", "/files/a%2Fb/", "", &cfg.PathOptions{TrailingSlash: cfg.IgnoreTrailingSlash}, true},
+		{"case sensitive", "/FILES/a%2Fb", "", nil, false},
+		{"case insensitive", "/FILES/a%2Fb", "", &cfg.PathOptions{CaseInsensitive: true}, true},
+		{"duplicate slashes", "//files//a%2Fb", "", nil, false},
+		{"merged slashes", "//files//a%2Fb", "", &cfg.PathOptions{MergeSlashes: true}, true},
+		{"decoded path", "/files/a%2Fb", "", &cfg.PathOptions{DecodePath: true}, false},
+		{"base path", "/v1/files/a%2Fb", "/v1", nil, true},
+		{"base path with a trailing slash", "/v1/files/a%2Fb", "/v1/", nil, true},
+		{"missing base path", "/files/a%2Fb", "/v1", nil, false},
+		{"longer base path segment", "/v10/files/a%2Fb", "/v1", nil, false},
+		{"case insensitive base path", "/V1/files/a%2Fb", "/v1", &cfg.PathOptions{CaseInsensitive: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "https://example.com"+tt.url, nil)
+			ctx := matcher.Context{HTTPRequest: req, BasePath: tt.basePath, PathOptions: tt.options}
+			result, err := matcher.NewRouteMatcher(route, ctx, memory.New()).Match()
+			require.NoError(t, err)
+			assert.Equal(t, tt.matched, result != nil)
+		})
+	}
+}
+
+func TestRouteMatcher_RedirectPath(t *testing.T) {
+	route := &cfg.Route{Method: http.MethodGet, Path: "/users/", Responses: []cfg.Response{{Status: http.StatusOK}}}
+	options := &cfg.PathOptions{TrailingSlash: cfg.RedirectTrailingSlash}
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		options  *cfg.PathOptions
+		location string
+	}{
+		{"missing trailing slash", http.MethodGet, "/v1/users", options, "/v1/users/"},
+		{"matched path", http.MethodGet, "/v1/users/", options, ""},
+		{"other method", http.MethodPost, "/v1/users", options, ""},
+		{"other path", http.MethodGet, "/v1/orders", options, ""},
+		{"strict trailing slash", http.MethodGet, "/v1/users", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, "https://example.com"+tt.url, nil)
+			ctx := matcher.Context{HTTPRequest: req, BasePath: "/v1", PathOptions: tt.options}
+			location, ok, err := matcher.NewRouteMatcher(route, ctx, memory.New()).RedirectPath()
+			require.NoError(t, err)
+			assert.Equal(t, tt.location != "", ok)
+			assert.Equal(t, tt.location, location)
+		})
+	}
+}
+
 func TestRouteMatcher_ResponseStrategy(t *testing.T) {
 	request, _ := http.NewRequest("GET", "https://example.com/how/are/you", nil)
 	request.Header.Add("Authorization", "Bearer")
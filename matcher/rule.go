@@ -1,12 +1,15 @@
 package matcher
 
 import (
-	"github.com/mockingio/engine/persistent"
+	"net"
 	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 
 	cfg "github.com/mockingio/engine/mock"
+	"github.com/mockingio/engine/persistent"
 )
 
 func NewRuleMatcher(route *cfg.Route, rule *cfg.Rule, req Context, db persistent.Persistent) *RuleMatcher {
@@ -26,43 +29,126 @@ type RuleMatcher struct {
 }
 
 func (r *RuleMatcher) Match() (bool, error) {
-	value, err := r.GetTargetValue()
+	if r.rule.Target == cfg.Expression {
+		matched, err := r.matchExpression()
+		if err != nil {
+			return false, err
+		}
+		return matched != r.rule.Negate, nil
+	}
+
+	// the unknown operators are reported by Validate, the rule does not match, even if it is negated
+	if !r.rule.Operator.IsKnown() {
+		return false, nil
+	}
+
+	if valuesFn, ok := multiValueTargets[r.rule.Target]; ok && (r.rule.MultiValue == cfg.AnyValue || r.rule.MultiValue == cfg.AllValues) {
+		matched, err := matchValues(r.rule, valuesFn(r.rule.Modifier, r.rule.IgnoreNameCase, r.req))
+		if err != nil {
+			return false, err
+		}
+		return matched != r.rule.Negate, nil
+	}
+
+	value, present, err := r.targetValue()
 	if err != nil {
 		return false, errors.Wrap(err, "get target value")
 	}
 
-	rule := r.rule
+	matched, err := match(r.rule, value, present)
+	if err != nil {
+		return false, err
+	}
+
+	return matched != r.rule.Negate, nil
+}
 
+func (r *RuleMatcher) GetTargetValue() (string, error) {
+	value, _, err := r.targetValue()
+	return value, err
+}
+
+func (r *RuleMatcher) targetValue() (string, bool, error) {
+	if valuesFn, ok := multiValueTargets[r.rule.Target]; ok {
+		value, present := selectValue(r.rule.MultiValue, valuesFn(r.rule.Modifier, r.rule.IgnoreNameCase, r.req))
+		return value, present, nil
+	}
+
+	if targetFn, ok := targets[r.rule.Target]; ok {
+		return targetFn(r.route, r.rule.Modifier, r.req, r.db)
+	}
+
+	return "", false, nil
+}
+
+// match applies the operator of the rule to the value of the target, a missing target only matches absent
+func match(rule *cfg.Rule, value string, present bool) (bool, error) {
 	switch rule.Operator {
+	case cfg.Exists:
+		return present, nil
+	case cfg.Absent:
+		return !present, nil
 	case cfg.Regex:
 		matched, err := regexp.MatchString(rule.Value, value)
 		if err != nil {
 			return false, errors.Wrap(err, "regex match string")
 		}
-		return matched, nil
+		return matched && present, nil
 	case cfg.Equal:
-		return value == rule.Value, nil
+		return value == rule.Value && present, nil
+	case cfg.NotEqual:
+		return value != rule.Value && present, nil
+	case cfg.CaseInsensitiveEqual:
+		return strings.EqualFold(value, rule.Value) && present, nil
+	case cfg.Contains:
+		return strings.Contains(value, rule.Value) && present, nil
+	case cfg.StartsWith:
+		return strings.HasPrefix(value, rule.Value) && present, nil
+	case cfg.EndsWith:
+		return strings.HasSuffix(value, rule.Value) && present, nil
+	case cfg.In:
+		for _, v := range rule.Values {
+			if value == v {
+				return present, nil
+			}
+		}
+		return false, nil
+	case cfg.CIDR:
+		_, network, err := net.ParseCIDR(strings.TrimSpace(rule.Value))
+		if err != nil {
+			return false, errors.Wrap(err, "parse cidr value")
+		}
+		ip := net.ParseIP(value)
+		return ip != nil && network.Contains(ip) && present, nil
+	case cfg.GreaterThan, cfg.GreaterThanOrEqual, cfg.LessThan, cfg.LessThanOrEqual:
+		return compare(rule.Operator, value, rule.Value, present)
+	case cfg.JSONEquals, cfg.JSONContains:
+		return matchJSON(rule.Operator, value, rule.Value, present)
 	default:
 		return false, nil
 	}
 }
 
-func (r *RuleMatcher) GetTargetValue() (string, error) {
-	if targetFn, ok := targets[r.rule.Target]; ok {
-		return targetFn(r.route, r.rule.Modifier, r.req, r.db)
+// compare compares the value of the target to the value of the rule as numbers, a target which is not a number does not match
+func compare(operator cfg.Operator, value, ruleValue string, present bool) (bool, error) {
+	expected, err := strconv.ParseFloat(strings.TrimSpace(ruleValue), 64)
+	if err != nil {
+		return false, errors.Wrapf(err, "parse %s value", operator)
 	}
 
-	return "", nil
-}
-
-func param(p string) (string, bool) {
-	if p == "" {
-		return "", false
+	actual, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || !present {
+		return false, nil
 	}
 
-	if string(p[0]) == ":" {
-		return p[1:], true
+	switch operator {
+	case cfg.GreaterThan:
+		return actual > expected, nil
+	case cfg.GreaterThanOrEqual:
+		return actual >= expected, nil
+	case cfg.LessThan:
+		return actual < expected, nil
+	default:
+		return actual <= expected, nil
 	}
-
-	return "", false
 }
@@ -2,8 +2,10 @@ package matcher_test
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -87,16 +89,242 @@ func TestRuleMatcher_Match(t *testing.T) {
 			false,
 			false,
 		},
+		{
+			"operator = NotEqual, found match",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.QueryString, Modifier: "name", Value: "jane", Operator: cfg.NotEqual},
+			true,
+			false,
+		},
+		{
+			"operator = NotEqual, missing target",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.QueryString, Modifier: "random", Value: "jane", Operator: cfg.NotEqual},
+			false,
+			false,
+		},
+		{
+			"operator = CaseInsensitiveEqual, found match",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.Header, Modifier: "Authorization", Value: "bearer 123", Operator: cfg.CaseInsensitiveEqual},
+			true,
+			false,
+		},
+		{
+			"operator = Contains, found match",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.Body, Modifier: ".address.street", Value: "Road", Operator: cfg.Contains},
+			true,
+			false,
+		},
+		{
+			"operator = StartsWith, found match",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.Header, Modifier: "Authorization", Value: "Bearer ", Operator: cfg.StartsWith},
+			true,
+			false,
+		},
+		{
+			"operator = EndsWith, found not match",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.Cookie, Modifier: "Token", Value: "456", Operator: cfg.EndsWith},
+			false,
+			false,
+		},
+		{
+			"operator = GreaterThan, found match",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.RequestNumber, Value: "1.5", Operator: cfg.GreaterThan},
+			true,
+			false,
+		},
+		{
+			"operator = GreaterThanOrEqual, found not match",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.RequestNumber, Value: "3", Operator: cfg.GreaterThanOrEqual},
+			false,
+			false,
+		},
+		{
+			"operator = LessThan, found match",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.Body, Modifier: ".address.postcode", Value: "3000", Operator: cfg.LessThan},
+			true,
+			false,
+		},
+		{
+			"operator = LessThanOrEqual, found match",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.RequestNumber, Value: "2", Operator: cfg.LessThanOrEqual},
+			true,
+			false,
+		},
+		{
+			"operator = LessThan, target is not a number",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.QueryString, Modifier: "name", Value: "3000", Operator: cfg.LessThan},
+			false,
+			false,
+		},
+		{
+			"operator = LessThan, invalid value",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.RequestNumber, Value: "two", Operator: cfg.LessThan},
+			false,
+			true,
+		},
+		{
+			"operator = Exists, found match",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.Cookie, Modifier: "Token", Operator: cfg.Exists},
+			true,
+			false,
+		},
+		{
+			"operator = Exists, found not match",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.Header, Modifier: "X-Random", Operator: cfg.Exists},
+			false,
+			false,
+		},
+		{
+			"operator = Absent, found match",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.Body, Modifier: ".address.random", Operator: cfg.Absent},
+			true,
+			false,
+		},
+		{
+			"operator = In, found match",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.QueryString, Modifier: "name", Values: []string{"jane", "joe"}, Operator: cfg.In},
+			true,
+			false,
+		},
+		{
+			"operator = In, found not match",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.QueryString, Modifier: "name", Values: []string{"jane", "john"}, Operator: cfg.In},
+			false,
+			false,
+		},
+		{
+			"target = Expression, found match",
+			&cfg.Route{Path: "/api/:object/:action"},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.Expression, Value: `body.address.postcode == "2234" && headers["Authorization"].startsWith("Bearer") && params.object == "person"`},
+			true,
+			false,
+		},
+		{
+			"target = Expression, found match on the other variables",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.Expression, Value: `method == "POST" && path == "/api/person/detail" && query.name == "joe" && cookies.Token == "Token 123" && request_number > 1 && session.id == "123456"`},
+			true,
+			false,
+		},
+		{
+			"target = Expression, found not match",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.Expression, Value: `body.name.size() > 3`},
+			false,
+			false,
+		},
+		{
+			"target = Expression, missing key, found not match",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.Expression, Value: `headers["X-Tier"] == "gold"`},
+			false,
+			false,
+		},
+		{
+			"target = Expression, lower case header name, found match",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.Expression, Value: `headers["authorization"] == "Bearer 123" && headers["Authorization"] == "Bearer 123"`},
+			true,
+			false,
+		},
+		{
+			"target = Expression, scenario state of the session, found match",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.Expression, Value: `session.scenarios.order == "shipped" && session.scenarios.cart == "Started"`},
+			true,
+			false,
+		},
+		{
+			"target = Expression, negate, found match",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.Expression, Value: `"X-Tier" in headers`, Negate: true},
+			true,
+			false,
+		},
+		{
+			"target = Expression, invalid expression",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.Expression, Value: `body.name ==`},
+			false,
+			true,
+		},
+		{
+			"target = Expression, not a bool",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.Expression, Value: `body.name`},
+			false,
+			true,
+		},
+		{
+			"negate, found match",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.Header, Modifier: "Authorization", Value: "^Basic ", Operator: cfg.Regex, Negate: true},
+			true,
+			false,
+		},
+		{
+			"negate, found not match",
+			&cfg.Route{},
+			newHTTPRequest(),
+			&cfg.Rule{Target: cfg.Cookie, Modifier: "Token", Operator: cfg.Exists, Negate: true},
+			false,
+			false,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mem := memory.New()
 			_ = mem.Set(context.Background(), req.CountID(), 2)
+			_ = mem.Set(context.Background(), req.ScenarioID("order"), "shipped")
 
 			matched, err := matcher.NewRuleMatcher(tt.route, tt.rule, matcher.Context{
 				HTTPRequest: tt.request,
 				SessionID:   sessionID,
+				Scenarios:   []string{"cart", "order"},
 			}, mem).Match()
 			if tt.error {
 				require.Error(t, err)
@@ -145,6 +373,13 @@ func TestRuleMatcher_GetTargetValue(t *testing.T) {
 		{&cfg.Route{Path: "/api/:object/:action/:something"}, newHTTPRequest(), &cfg.Rule{Target: cfg.RouteParam, Modifier: "random"}, ""},
 		{route, newHTTPRequest(), &cfg.Rule{Target: cfg.RequestNumber}, "2"},
 		{route, newHTTPRequest(), &cfg.Rule{Target: cfg.Target("random target")}, ""},
+		{route, newHTTPRequest(), &cfg.Rule{Target: cfg.Method}, "POST"},
+		{route, newHTTPRequest(), &cfg.Rule{Target: cfg.Path}, "/api/person/detail"},
+		{route, newHTTPRequest(), &cfg.Rule{Target: cfg.Host}, "hi.com"},
+		{route, newHTTPRequest(), &cfg.Rule{Target: cfg.Scheme}, "https"},
+		{route, newHTTPRequest(), &cfg.Rule{Target: cfg.URL}, "https://hi.com/api/person/detail?name=joe"},
+		{route, newHTTPRequest(), &cfg.Rule{Target: cfg.BasicAuthUser}, ""},
+		{route, newHTTPRequest(), &cfg.Rule{Target: cfg.JWTClaim, Modifier: "sub"}, ""},
 	}
 
 	for _, tt := range tests {
@@ -160,6 +395,164 @@ func TestRuleMatcher_GetTargetValue(t *testing.T) {
 	}
 }
 
+func TestRuleMatcher_Match_RequestMetadata(t *testing.T) {
+	// {"alg":"none"} and {"sub":"user-1","org":{"id":42,"roles":["admin"]},"verified":true}, the signature is not verified
+	token := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"user-1","org":{"id":42,"roles":["admin"]},"verified":true}`)) + ".signature"
+
+	newRequest := func(configure func(r *http.Request)) *http.Request {
+		req := httptest.NewRequest("GET", "http://tenant.example.com:8080/orders?page=2", nil)
+		req.RemoteAddr = "192.168.1.20:53422"
+		configure(req)
+		return req
+	}
+
+	tests := []struct {
+		name    string
+		request *http.Request
+		rule    *cfg.Rule
+		matched bool
+	}{
+		{
+			"host without the port",
+			newRequest(func(r *http.Request) {}),
+			&cfg.Rule{Target: cfg.Host, Value: "tenant.example.com", Operator: cfg.Equal},
+			true,
+		},
+		{
+			"scheme of the proxy",
+			newRequest(func(r *http.Request) { r.Header.Set("X-Forwarded-Proto", "HTTPS") }),
+			&cfg.Rule{Target: cfg.Scheme, Value: "https", Operator: cfg.Equal},
+			true,
+		},
+		{
+			"full url",
+			newRequest(func(r *http.Request) {}),
+			&cfg.Rule{Target: cfg.URL, Value: "http://tenant.example.com:8080/orders?page=2", Operator: cfg.Equal},
+			true,
+		},
+		{
+			"remote ip in the network",
+			newRequest(func(r *http.Request) {}),
+			&cfg.Rule{Target: cfg.RemoteIP, Value: "192.168.0.0/16", Operator: cfg.CIDR},
+			true,
+		},
+		{
+			"remote ip out of the network",
+			newRequest(func(r *http.Request) {}),
+			&cfg.Rule{Target: cfg.RemoteIP, Value: "10.0.0.0/8", Operator: cfg.CIDR},
+			false,
+		},
+		{
+			"remote ip of X-Forwarded-For",
+			newRequest(func(r *http.Request) { r.Header.Set("X-Forwarded-For", "10.1.2.3, 192.168.1.1") }),
+			&cfg.Rule{Target: cfg.RemoteIP, Value: "10.0.0.0/8", Operator: cfg.CIDR},
+			true,
+		},
+		{
+			"ipv6 remote ip",
+			newRequest(func(r *http.Request) { r.RemoteAddr = "[2001:db8::1]:53422" }),
+			&cfg.Rule{Target: cfg.RemoteIP, Value: "2001:db8::/32", Operator: cfg.CIDR},
+			true,
+		},
+		{
+			"header which is not an ip",
+			newRequest(func(r *http.Request) { r.Header.Set("X-Client", "unknown") }),
+			&cfg.Rule{Target: cfg.Header, Modifier: "X-Client", Value: "0.0.0.0/0", Operator: cfg.CIDR},
+			false,
+		},
+		{
+			"basic auth user",
+			newRequest(func(r *http.Request) { r.SetBasicAuth("joe", "secret") }),
+			&cfg.Rule{Target: cfg.BasicAuthUser, Value: "joe", Operator: cfg.Equal},
+			true,
+		},
+		{
+			"no basic auth",
+			newRequest(func(r *http.Request) {}),
+			&cfg.Rule{Target: cfg.BasicAuthUser, Operator: cfg.Absent},
+			true,
+		},
+		{
+			"jwt claim",
+			newRequest(func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) }),
+			&cfg.Rule{Target: cfg.JWTClaim, Modifier: "sub", Value: "user-1", Operator: cfg.Equal},
+			true,
+		},
+		{
+			"nested jwt claim",
+			newRequest(func(r *http.Request) { r.Header.Set("Authorization", "bearer "+token) }),
+			&cfg.Rule{Target: cfg.JWTClaim, Modifier: "org.id", Value: "42", Operator: cfg.GreaterThanOrEqual},
+			true,
+		},
+		{
+			"jwt claim in a list",
+			newRequest(func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) }),
+			&cfg.Rule{Target: cfg.JWTClaim, Modifier: "org.roles.0", Value: "admin", Operator: cfg.Equal},
+			true,
+		},
+		{
+			"boolean jwt claim",
+			newRequest(func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) }),
+			&cfg.Rule{Target: cfg.JWTClaim, Modifier: "verified", Value: "true", Operator: cfg.Equal},
+			true,
+		},
+		{
+			"missing jwt claim",
+			newRequest(func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) }),
+			&cfg.Rule{Target: cfg.JWTClaim, Modifier: "org.name", Operator: cfg.Exists},
+			false,
+		},
+		{
+			"invalid token",
+			newRequest(func(r *http.Request) { r.Header.Set("Authorization", "Bearer not-a-token") }),
+			&cfg.Rule{Target: cfg.JWTClaim, Modifier: "sub", Operator: cfg.Absent},
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, err := matcher.NewRuleMatcher(&cfg.Route{}, tt.rule, matcher.Context{HTTPRequest: tt.request}, memory.New()).Match()
+			require.NoError(t, err)
+			assert.Equal(t, tt.matched, matched)
+		})
+	}
+}
+
+func TestRuleMatcher_Match_TypedBody(t *testing.T) {
+	newRequest := func(body string) *http.Request {
+		req, _ := http.NewRequest(http.MethodPost, "https://hi.com/orders", strings.NewReader(body))
+		return req
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		rule    *cfg.Rule
+		matched bool
+	}{
+		{"number", `{"quantity": 3}`, &cfg.Rule{Target: cfg.Body, Modifier: ".quantity", Value: "2", Operator: cfg.GreaterThan}, true},
+		{"decimal number", `{"price": 9.99}`, &cfg.Rule{Target: cfg.Body, Modifier: ".price", Value: "9.99", Operator: cfg.Equal}, true},
+		{"boolean", `{"paid": true}`, &cfg.Rule{Target: cfg.Body, Modifier: ".paid", Value: "true", Operator: cfg.Equal}, true},
+		{"array", `{"tags": ["a", "b"]}`, &cfg.Rule{Target: cfg.Body, Modifier: ".tags", Value: `["a","b"]`, Operator: cfg.Equal}, true},
+		{"object", `{"user": {"id": 1}}`, &cfg.Rule{Target: cfg.Body, Modifier: ".user", Value: `{"id":1}`, Operator: cfg.Equal}, true},
+		{"array length", `{"items": [1, 2, 3, 4]}`, &cfg.Rule{Target: cfg.Body, Modifier: ".items | length", Value: "3", Operator: cfg.GreaterThan}, true},
+		{"array root", `[{"sku": "SKU-1"}]`, &cfg.Rule{Target: cfg.Body, Modifier: ".[0].sku", Value: "SKU-1", Operator: cfg.Equal}, true},
+		{"scalar root", `42`, &cfg.Rule{Target: cfg.Body, Modifier: ".", Value: "42", Operator: cfg.Equal}, true},
+		{"null value", `{"user": null}`, &cfg.Rule{Target: cfg.Body, Modifier: ".user", Operator: cfg.Absent}, true},
+		{"body which is not JSON", `name=joe`, &cfg.Rule{Target: cfg.Body, Modifier: ".name", Operator: cfg.Absent}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, err := matcher.NewRuleMatcher(&cfg.Route{}, tt.rule, matcher.Context{HTTPRequest: newRequest(tt.body)}, memory.New()).Match()
+			require.NoError(t, err)
+			assert.Equal(t, tt.matched, matched)
+		})
+	}
+}
+
 func newHTTPRequest() *http.Request {
 	req, _ := http.NewRequest(
 		"POST",
@@ -0,0 +1,29 @@
+package matcher
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	cfg "github.com/mockingio/engine/mock"
+	"github.com/mockingio/engine/persistent"
+)
+
+// ScenarioID is the key of the state of the scenario in the session
+func (r Context) ScenarioID(scenario string) string {
+	return fmt.Sprintf("%s-%s-%s-scenario", r.MockID, scenario, r.SessionID)
+}
+
+// scenarioState returns the state of the scenario in the session, cfg.StartedState if it never moved
+func scenarioState(req Context, db persistent.Persistent, scenario string) (string, error) {
+	value, err := db.Get(req.HTTPRequest.Context(), req.ScenarioID(scenario))
+	if err != nil {
+		return "", errors.Wrap(err, "get scenario state")
+	}
+
+	state, ok := value.(string)
+	if !ok || state == "" {
+		return cfg.StartedState, nil
+	}
+	return state, nil
+}
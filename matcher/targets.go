@@ -1,8 +1,10 @@
 package matcher
 
 import (
+	"encoding/base64"
 	"encoding/json"
-	"io/ioutil"
+	"net"
+	"net/http"
 	"strconv"
 	"strings"
 
@@ -14,105 +16,218 @@ import (
 )
 
 var targets = map[cfg.Target]getTargetValueFn{
-	cfg.Header:        getValueFromHeader,
-	cfg.Cookie:        getValueFromCookie,
-	cfg.QueryString:   getValueFromQueryString,
 	cfg.RequestNumber: getRequestNumber,
 	cfg.RouteParam:    getValueFromRouteParam,
 	cfg.Body:          getValueFromBody,
+	cfg.Method:        getMethod,
+	cfg.Path:          getPath,
+	cfg.Host:          getHost,
+	cfg.Scheme:        getScheme,
+	cfg.RemoteIP:      getRemoteIP,
+	cfg.URL:           getURL,
+	cfg.BasicAuthUser: getBasicAuthUser,
+	cfg.JWTClaim:      getJWTClaim,
+
+	cfg.FormField:                getFormField,
+	cfg.MultipartField:           getMultipartField,
+	cfg.MultipartFileName:        getMultipartFileName,
+	cfg.MultipartFileContentType: getMultipartFileContentType,
+	cfg.MultipartFileSize:        getMultipartFileSize,
+	cfg.XMLBody:                  getValueFromXMLBody,
+	cfg.SOAPAction:               getSOAPAction,
+	cfg.JSONBody:                 getValueFromJSONBody,
 }
 
-type getTargetValueFn func(route *cfg.Route, modifier string, req Context, db persistent.Persistent) (string, error)
+// getTargetValueFn returns the value of the target, and whether the target is present in the request
+type getTargetValueFn func(route *cfg.Route, modifier string, req Context, db persistent.Persistent) (string, bool, error)
 
-func getValueFromHeader(_ *cfg.Route, modifier string, req Context, _ persistent.Persistent) (string, error) {
-	return req.HTTPRequest.Header.Get(modifier), nil
+func getRequestNumber(_ *cfg.Route, _ string, req Context, db persistent.Persistent) (string, bool, error) {
+	value, err := db.GetInt(req.HTTPRequest.Context(), req.CountID())
+	if err != nil {
+		return "", false, err
+	}
+	return strconv.Itoa(value), true, nil
 }
 
-func getValueFromCookie(_ *cfg.Route, modifier string, req Context, _ persistent.Persistent) (string, error) {
-	cookies := req.HTTPRequest.Cookies()
-	for _, c := range cookies {
-		if c.Name == modifier {
-			return c.Value, nil
-		}
-	}
-	return "", nil
+func getValueFromRouteParam(route *cfg.Route, modifier string, req Context, _ persistent.Persistent) (string, bool, error) {
+	value, ok := routeParams(route, req)[modifier]
+	return value, ok, nil
 }
 
-func getValueFromQueryString(_ *cfg.Route, modifier string, req Context, _ persistent.Persistent) (string, error) {
-	return req.HTTPRequest.URL.Query().Get(modifier), nil
+// routeParams returns the params of the path of the route, they are matched again if the route matcher did not set them
+func routeParams(route *cfg.Route, req Context) map[string]string {
+	if req.Params != nil || route == nil {
+		return req.Params
+	}
+
+	params, _, _ := matchRoutePath(route, req)
+	return params
 }
 
-func getRequestNumber(_ *cfg.Route, _ string, req Context, db persistent.Persistent) (string, error) {
-	value, err := db.GetInt(req.HTTPRequest.Context(), req.CountID())
+// getValueFromBody returns the body, or the result of the jq query of the modifier on the JSON body.
+// The results which are not strings, like numbers, booleans or arrays, are returned as JSON
+func getValueFromBody(_ *cfg.Route, modifier string, req Context, _ persistent.Persistent) (string, bool, error) {
+	value, err := req.Body()
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
-	return strconv.Itoa(value), nil
-}
 
-func getValueFromRouteParam(route *cfg.Route, modifier string, req Context, _ persistent.Persistent) (string, error) {
-	templateParts := strings.Split(route.Path, "/")
-	actualParts := strings.Split(req.HTTPRequest.URL.Path, "/")
-	if len(templateParts) != len(actualParts) {
-		return "", nil
+	if len(value) == 0 {
+		return "", false, nil
 	}
 
-	for i, templatePart := range templateParts {
-		if p, ok := param(templatePart); ok {
-			if p == modifier {
-				return actualParts[i], nil
-			}
-		}
+	if modifier == "" {
+		return string(value), true, nil
+	}
+
+	// a body which is not JSON has no value for the query
+	var input interface{}
+	if err := json.Unmarshal(value, &input); err != nil {
+		return "", false, nil
+	}
+
+	query, err := gojq.Parse(modifier)
+	if err != nil {
+		return "", false, nil
+	}
+
+	iter := query.Run(input)
+	v, ok := iter.Next()
+	if !ok || v == nil {
+		return "", false, nil
+	}
+
+	if err, ok := v.(error); ok {
+		return "", false, errors.Wrapf(err, "unable to parse json query: %v", modifier)
+	}
+
+	result, err := toString(v)
+	return result, true, err
+}
+
+func getMethod(_ *cfg.Route, _ string, req Context, _ persistent.Persistent) (string, bool, error) {
+	return req.HTTPRequest.Method, true, nil
+}
+
+func getPath(_ *cfg.Route, _ string, req Context, _ persistent.Persistent) (string, bool, error) {
+	return req.HTTPRequest.URL.Path, true, nil
+}
+
+// getHost returns the host of the request, without the port
+func getHost(_ *cfg.Route, _ string, req Context, _ persistent.Persistent) (string, bool, error) {
+	host := req.HTTPRequest.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
 	}
+	return host, host != "", nil
+}
 
-	return "", nil
+func getScheme(_ *cfg.Route, _ string, req Context, _ persistent.Persistent) (string, bool, error) {
+	return scheme(req.HTTPRequest), true, nil
 }
 
-func getValueFromBody(_ *cfg.Route, modifier string, req Context, _ persistent.Persistent) (string, error) {
+// getRemoteIP returns the address of the client, the first address of X-Forwarded-For or X-Real-IP
+// when the request went through a proxy
+func getRemoteIP(_ *cfg.Route, _ string, req Context, _ persistent.Persistent) (string, bool, error) {
 	httpRequest := req.HTTPRequest
-	if httpRequest.Body == nil {
-		return "", nil
+	if forwarded := httpRequest.Header.Get("X-Forwarded-For"); forwarded != "" {
+		return strings.TrimSpace(strings.Split(forwarded, ",")[0]), true, nil
+	}
+	if realIP := httpRequest.Header.Get("X-Real-IP"); realIP != "" {
+		return strings.TrimSpace(realIP), true, nil
 	}
 
-	value, err := ioutil.ReadAll(httpRequest.Body)
+	host, _, err := net.SplitHostPort(httpRequest.RemoteAddr)
 	if err != nil {
-		return "", errors.Wrap(err, "read request body")
+		host = httpRequest.RemoteAddr
 	}
+	return host, host != "", nil
+}
 
-	if string(value) == "" {
-		return "", nil
+// getURL returns the full URL of the request, with the scheme and the host
+func getURL(_ *cfg.Route, _ string, req Context, _ persistent.Persistent) (string, bool, error) {
+	httpRequest := req.HTTPRequest
+	u := *httpRequest.URL
+	u.Scheme = scheme(httpRequest)
+	if u.Host == "" {
+		u.Host = httpRequest.Host
 	}
+	return u.String(), true, nil
+}
 
-	if modifier == "" {
-		return string(value), nil
+func getBasicAuthUser(_ *cfg.Route, _ string, req Context, _ persistent.Persistent) (string, bool, error) {
+	user, _, ok := req.HTTPRequest.BasicAuth()
+	return user, ok, nil
+}
+
+// getJWTClaim returns the claim of the bearer token at the path of the modifier, like org.id.
+// The token is decoded without being verified, a request without a valid token has no claim
+func getJWTClaim(_ *cfg.Route, modifier string, req Context, _ persistent.Persistent) (string, bool, error) {
+	authorization := req.HTTPRequest.Header.Get("Authorization")
+	if len(authorization) < 7 || !strings.EqualFold(authorization[:7], "Bearer ") {
+		return "", false, nil
 	}
 
-	input := map[string]interface{}{}
-	if err := json.Unmarshal(value, &input); err != nil {
-		return "", errors.Wrap(err, "unmarshal body")
+	parts := strings.Split(strings.TrimSpace(authorization[7:]), ".")
+	if len(parts) != 3 {
+		return "", false, nil
 	}
 
-	query, err := gojq.Parse(modifier)
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
 	if err != nil {
-		return "", nil
+		return "", false, nil
 	}
 
-	iter := query.Run(input)
-	for {
-		v, ok := iter.Next()
-		if !ok {
-			break
-		}
+	var claim interface{}
+	if err := json.Unmarshal(payload, &claim); err != nil {
+		return "", false, nil
+	}
 
-		if v == nil {
-			return "", nil
+	for _, key := range strings.Split(modifier, ".") {
+		switch v := claim.(type) {
+		case map[string]interface{}:
+			claim = v[key]
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return "", false, nil
+			}
+			claim = v[i]
+		default:
+			return "", false, nil
 		}
+	}
 
-		if err, ok := v.(error); ok {
-			return "", errors.Wrapf(err, "unable to parse json query: %v", modifier)
-		}
+	if claim == nil {
+		return "", false, nil
+	}
+
+	value, err := toString(claim)
+	return value, true, err
+}
 
-		return v.(string), nil // nolint
+func scheme(httpRequest *http.Request) string {
+	switch {
+	case httpRequest.TLS != nil:
+		return "https"
+	case httpRequest.Header.Get("X-Forwarded-Proto") != "":
+		return strings.ToLower(httpRequest.Header.Get("X-Forwarded-Proto"))
+	case httpRequest.URL.Scheme != "":
+		return httpRequest.URL.Scheme
+	}
+
+	return "http"
+}
+
+// toString returns the value of a decoded JSON, the strings are not quoted and the other values are encoded
+func toString(value interface{}) (string, error) {
+	if text, ok := value.(string); ok {
+		return text, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", errors.Wrap(err, "encode value")
 	}
-	return "", nil
+	return string(data), nil
 }
@@ -0,0 +1,121 @@
+package matcher
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	cfg "github.com/mockingio/engine/mock"
+)
+
+// multiValueTargets are the targets which can be sent several times, their values are in the order of the request
+var multiValueTargets = map[cfg.Target]getTargetValuesFn{
+	cfg.Header:      getHeaderValues,
+	cfg.Cookie:      getCookieValues,
+	cfg.QueryString: getQueryValues,
+}
+
+// getTargetValuesFn returns the values of the target named name, the name is not case sensitive if ignoreCase is true
+type getTargetValuesFn func(name string, ignoreCase bool, req Context) []string
+
+type queryParam struct {
+	name  string
+	value string
+}
+
+// getHeaderValues returns the values of the header, one by header line. The header names are not case sensitive
+func getHeaderValues(name string, _ bool, req Context) []string {
+	return req.HTTPRequest.Header.Values(name)
+}
+
+func getCookieValues(name string, ignoreCase bool, req Context) []string {
+	var values []string
+	for _, cookie := range req.HTTPRequest.Cookies() {
+		if sameName(cookie.Name, name, ignoreCase) {
+			values = append(values, cookie.Value)
+		}
+	}
+	return values
+}
+
+func getQueryValues(name string, ignoreCase bool, req Context) []string {
+	var values []string
+	for _, param := range queryParams(req.HTTPRequest.URL.RawQuery) {
+		if sameName(param.name, name, ignoreCase) {
+			values = append(values, param.value)
+		}
+	}
+	return values
+}
+
+// queryParams parses the query string in order. Unlike url.ParseQuery, the params are kept when they are not escaped
+// properly or are separated by a semicolon, and a param without value, like ?debug, has an empty value
+func queryParams(rawQuery string) []queryParam {
+	var params []queryParam
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+
+		name, value := pair, ""
+		if i := strings.Index(pair, "="); i >= 0 {
+			name, value = pair[:i], pair[i+1:]
+		}
+		params = append(params, queryParam{name: unescape(name), value: unescape(value)})
+	}
+	return params
+}
+
+// unescape unescapes a query component, the components which are not escaped properly are kept as they are
+func unescape(s string) string {
+	if unescaped, err := url.QueryUnescape(s); err == nil {
+		return unescaped
+	}
+	return s
+}
+
+func sameName(name, expected string, ignoreCase bool) bool {
+	if ignoreCase {
+		return strings.EqualFold(name, expected)
+	}
+	return name == expected
+}
+
+// selectValue returns the value of the multi value mode of the rule, the first value if it is not set
+func selectValue(mode string, values []string) (string, bool) {
+	switch mode {
+	case cfg.ValueCount:
+		return strconv.Itoa(len(values)), true
+	case "", cfg.AnyValue, cfg.AllValues:
+		if len(values) == 0 {
+			return "", false
+		}
+		return values[0], true
+	}
+
+	index, err := strconv.Atoi(mode)
+	if err != nil || index < 0 || index >= len(values) {
+		return "", false
+	}
+	return values[index], true
+}
+
+// matchValues matches each value of the target, a target without value is matched as missing
+func matchValues(rule *cfg.Rule, values []string) (bool, error) {
+	if len(values) == 0 {
+		return match(rule, "", false)
+	}
+
+	all := rule.MultiValue == cfg.AllValues
+	for _, value := range values {
+		matched, err := match(rule, value, true)
+		if err != nil {
+			return false, err
+		}
+		if matched != all {
+			return matched, nil
+		}
+	}
+
+	return all, nil
+}
@@ -0,0 +1,57 @@
+package matcher_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mockingio/engine/matcher"
+	cfg "github.com/mockingio/engine/mock"
+	"github.com/mockingio/engine/persistent/memory"
+)
+
+func TestRuleMatcher_Match_MultiValue(t *testing.T) {
+	newRequest := func() *http.Request {
+		req, _ := http.NewRequest(http.MethodGet, "/search?tag=a&tag=b&Sort=name&debug&q=100%&f=a;b", nil)
+		req.Header.Add("X-Tag", "a")
+		req.Header.Add("X-Tag", "b")
+		req.AddCookie(&http.Cookie{Name: "Session", Value: "abc"})
+		return req
+	}
+
+	tests := []struct {
+		name    string
+		rule    *cfg.Rule
+		matched bool
+	}{
+		{"first value by default", &cfg.Rule{Target: cfg.QueryString, Modifier: "tag", Value: "a", Operator: cfg.Equal}, true},
+		{"any value", &cfg.Rule{Target: cfg.QueryString, Modifier: "tag", Value: "b", Operator: cfg.Equal, MultiValue: cfg.AnyValue}, true},
+		{"any value, none matches", &cfg.Rule{Target: cfg.QueryString, Modifier: "tag", Value: "c", Operator: cfg.Equal, MultiValue: cfg.AnyValue}, false},
+		{"all values", &cfg.Rule{Target: cfg.QueryString, Modifier: "tag", Value: "^[ab]$", Operator: cfg.Regex, MultiValue: cfg.AllValues}, true},
+		{"all values, one does not match", &cfg.Rule{Target: cfg.QueryString, Modifier: "tag", Value: "a", Operator: cfg.Equal, MultiValue: cfg.AllValues}, false},
+		{"all values of a missing param", &cfg.Rule{Target: cfg.QueryString, Modifier: "page", Operator: cfg.Absent, MultiValue: cfg.AllValues}, true},
+		{"value at an index", &cfg.Rule{Target: cfg.QueryString, Modifier: "tag", Value: "b", Operator: cfg.Equal, MultiValue: "1"}, true},
+		{"missing index", &cfg.Rule{Target: cfg.QueryString, Modifier: "tag", Operator: cfg.Absent, MultiValue: "2"}, true},
+		{"count", &cfg.Rule{Target: cfg.QueryString, Modifier: "tag", Value: "2", Operator: cfg.Equal, MultiValue: cfg.ValueCount}, true},
+		{"count of a missing param", &cfg.Rule{Target: cfg.QueryString, Modifier: "page", Value: "0", Operator: cfg.Equal, MultiValue: cfg.ValueCount}, true},
+		{"param without value", &cfg.Rule{Target: cfg.QueryString, Modifier: "debug", Operator: cfg.Exists}, true},
+		{"param not escaped", &cfg.Rule{Target: cfg.QueryString, Modifier: "q", Value: "100%", Operator: cfg.Equal}, true},
+		{"param with a semicolon", &cfg.Rule{Target: cfg.QueryString, Modifier: "f", Value: "a;b", Operator: cfg.Equal}, true},
+		{"case sensitive param name", &cfg.Rule{Target: cfg.QueryString, Modifier: "sort", Operator: cfg.Absent}, true},
+		{"case insensitive param name", &cfg.Rule{Target: cfg.QueryString, Modifier: "sort", Value: "name", Operator: cfg.Equal, IgnoreNameCase: true}, true},
+		{"any header value", &cfg.Rule{Target: cfg.Header, Modifier: "x-tag", Value: "b", Operator: cfg.Equal, MultiValue: cfg.AnyValue}, true},
+		{"header count", &cfg.Rule{Target: cfg.Header, Modifier: "X-Tag", Value: "1", Operator: cfg.GreaterThan, MultiValue: cfg.ValueCount}, true},
+		{"case insensitive cookie name", &cfg.Rule{Target: cfg.Cookie, Modifier: "session", Value: "abc", Operator: cfg.Equal, IgnoreNameCase: true}, true},
+		{"negated any value", &cfg.Rule{Target: cfg.Header, Modifier: "X-Tag", Value: "c", Operator: cfg.Equal, MultiValue: cfg.AnyValue, Negate: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, err := matcher.NewRuleMatcher(&cfg.Route{}, tt.rule, matcher.Context{HTTPRequest: newRequest()}, memory.New()).Match()
+			require.NoError(t, err)
+			assert.Equal(t, tt.matched, matched)
+		})
+	}
+}
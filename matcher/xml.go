@@ -0,0 +1,95 @@
+package matcher
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+	"github.com/pkg/errors"
+
+	cfg "github.com/mockingio/engine/mock"
+	"github.com/mockingio/engine/persistent"
+)
+
+// getValueFromXMLBody returns the result of the XPath of the modifier on the XML body, the text of the first
+// node for a node set. The prefixes of the XPath are the namespaces of the mock
+func getValueFromXMLBody(_ *cfg.Route, modifier string, req Context, _ persistent.Persistent) (string, bool, error) {
+	expr, err := xpath.Compile(modifier)
+	if err != nil {
+		return "", false, errors.Wrapf(err, "compile xpath %v", modifier)
+	}
+
+	body, err := req.Body()
+	if err != nil || len(bytes.TrimSpace(body)) == 0 {
+		return "", false, err
+	}
+
+	// a body which is not XML has no value for the XPath
+	doc, err := xmlquery.Parse(bytes.NewReader(body))
+	if err != nil {
+		return "", false, nil
+	}
+	usePrefixes(doc, req.Namespaces)
+
+	switch result := expr.Evaluate(xmlquery.CreateXPathNavigator(doc)).(type) {
+	case *xpath.NodeIterator:
+		if !result.MoveNext() {
+			return "", false, nil
+		}
+		return result.Current().Value(), true, nil
+	case float64:
+		return strconv.FormatFloat(result, 'f', -1, 64), true, nil
+	case bool:
+		return strconv.FormatBool(result), true, nil
+	case string:
+		return result, true, nil
+	}
+
+	return "", false, nil
+}
+
+// usePrefixes renames the prefixes of the document to the prefixes of the namespaces, so that the XPath
+// does not depend on the prefixes chosen by the client, or on the default namespace
+func usePrefixes(node *xmlquery.Node, namespaces map[string]string) {
+	if len(namespaces) == 0 {
+		return
+	}
+
+	prefixes := map[string]string{}
+	for prefix, uri := range namespaces {
+		prefixes[uri] = prefix
+	}
+
+	var walk func(n *xmlquery.Node)
+	walk = func(n *xmlquery.Node) {
+		if prefix, ok := prefixes[n.NamespaceURI]; ok && n.Type == xmlquery.ElementNode {
+			n.Prefix = prefix
+		}
+		for i, attr := range n.Attr {
+			if prefix, ok := prefixes[attr.NamespaceURI]; ok && attr.Name.Space != "" && attr.Name.Space != "xmlns" {
+				n.Attr[i].Name.Space = prefix
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(node)
+}
+
+// getSOAPAction returns the SOAPAction header of SOAP 1.1, or the action parameter of the content type of SOAP 1.2
+func getSOAPAction(_ *cfg.Route, _ string, req Context, _ persistent.Persistent) (string, bool, error) {
+	if values := req.HTTPRequest.Header.Values("SOAPAction"); len(values) > 0 {
+		return strings.Trim(values[0], `"`), true, nil
+	}
+
+	if mediaType, params := mediaType(req); mediaType == "application/soap+xml" {
+		if action, ok := params["action"]; ok {
+			return action, true, nil
+		}
+	}
+
+	return "", false, nil
+}
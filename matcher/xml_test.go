@@ -0,0 +1,135 @@
+package matcher_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mockingio/engine/matcher"
+	cfg "github.com/mockingio/engine/mock"
+	"github.com/mockingio/engine/persistent/memory"
+)
+
+const soapEnvelope = `<?xml version="1.0"?>
+<env:Envelope xmlns:env="http://schemas.xmlsoap.org/soap/envelope/" xmlns:ns1="http://example.com/stock">
+  <env:Body>
+    <ns1:GetPrice ns1:currency="EUR">
+      <ns1:Item>Apple</ns1:Item>
+      <ns1:Item>Pear</ns1:Item>
+    </ns1:GetPrice>
+  </env:Body>
+</env:Envelope>`
+
+const defaultNamespaceEnvelope = `<Envelope xmlns="http://schemas.xmlsoap.org/soap/envelope/">
+  <Body>
+    <GetPrice xmlns="http://example.com/stock"><Item>Apple</Item></GetPrice>
+  </Body>
+</Envelope>`
+
+func TestRuleMatcher_Match_XML(t *testing.T) {
+	namespaces := map[string]string{
+		"soap":  "http://schemas.xmlsoap.org/soap/envelope/",
+		"stock": "http://example.com/stock",
+	}
+
+	newRequest := func(body string, headers map[string]string) *http.Request {
+		req, _ := http.NewRequest(http.MethodPost, "/stock", strings.NewReader(body))
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		return req
+	}
+
+	tests := []struct {
+		name       string
+		request    *http.Request
+		namespaces map[string]string
+		rule       *cfg.Rule
+		matched    bool
+	}{
+		{
+			"xpath with the prefixes of the document",
+			newRequest(soapEnvelope, nil),
+			nil,
+			&cfg.Rule{Target: cfg.XMLBody, Modifier: "//ns1:GetPrice/ns1:Item", Value: "Apple", Operator: cfg.Equal},
+			true,
+		},
+		{
+			"xpath with the prefixes of the mock",
+			newRequest(soapEnvelope, nil),
+			namespaces,
+			&cfg.Rule{Target: cfg.XMLBody, Modifier: "/soap:Envelope/soap:Body/stock:GetPrice/stock:Item[2]", Value: "Pear", Operator: cfg.Equal},
+			true,
+		},
+		{
+			"xpath with the prefixes of the mock, default namespace",
+			newRequest(defaultNamespaceEnvelope, nil),
+			namespaces,
+			&cfg.Rule{Target: cfg.XMLBody, Modifier: "//stock:GetPrice/stock:Item", Value: "Apple", Operator: cfg.Equal},
+			true,
+		},
+		{
+			"attribute",
+			newRequest(soapEnvelope, nil),
+			namespaces,
+			&cfg.Rule{Target: cfg.XMLBody, Modifier: "//stock:GetPrice/@stock:currency", Value: "EUR", Operator: cfg.Equal},
+			true,
+		},
+		{
+			"count",
+			newRequest(soapEnvelope, nil),
+			namespaces,
+			&cfg.Rule{Target: cfg.XMLBody, Modifier: "count(//stock:Item)", Value: "2", Operator: cfg.Equal},
+			true,
+		},
+		{
+			"missing node",
+			newRequest(soapEnvelope, nil),
+			namespaces,
+			&cfg.Rule{Target: cfg.XMLBody, Modifier: "//stock:GetQuote", Operator: cfg.Absent},
+			true,
+		},
+		{
+			"body which is not XML",
+			newRequest(`{"item": "Apple"}`, nil),
+			namespaces,
+			&cfg.Rule{Target: cfg.XMLBody, Modifier: "//item", Operator: cfg.Exists},
+			false,
+		},
+		{
+			"SOAP 1.1 action",
+			newRequest(soapEnvelope, map[string]string{"SOAPAction": `"http://example.com/stock/GetPrice"`}),
+			nil,
+			&cfg.Rule{Target: cfg.SOAPAction, Value: "http://example.com/stock/GetPrice", Operator: cfg.Equal},
+			true,
+		},
+		{
+			"SOAP 1.2 action",
+			newRequest(soapEnvelope, map[string]string{"Content-Type": `application/soap+xml; charset=utf-8; action="http://example.com/stock/GetPrice"`}),
+			nil,
+			&cfg.Rule{Target: cfg.SOAPAction, Value: "GetPrice", Operator: cfg.EndsWith},
+			true,
+		},
+		{
+			"no SOAP action",
+			newRequest(soapEnvelope, map[string]string{"Content-Type": "text/xml"}),
+			nil,
+			&cfg.Rule{Target: cfg.SOAPAction, Operator: cfg.Absent},
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, err := matcher.NewRuleMatcher(&cfg.Route{}, tt.rule, matcher.Context{
+				HTTPRequest: tt.request,
+				Namespaces:  tt.namespaces,
+			}, memory.New()).Match()
+			require.NoError(t, err)
+			assert.Equal(t, tt.matched, matched)
+		})
+	}
+}
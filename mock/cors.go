@@ -0,0 +1,113 @@
+package mock
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var defaultCORSMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPut, http.MethodPatch, http.MethodPost, http.MethodDelete,
+}
+
+// CORS is the policy answering the preflight requests, and decorating the responses of the cross origin requests
+type CORS struct {
+	Enabled bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	// AllowOrigins are the allowed origins, all the origins are allowed if it is empty or contains *
+	AllowOrigins []string `yaml:"allow_origins,omitempty" json:"allow_origins,omitempty"`
+	// AllowMethods defaults to GET, HEAD, PUT, PATCH, POST and DELETE
+	AllowMethods []string `yaml:"allow_methods,omitempty" json:"allow_methods,omitempty"`
+	// AllowHeaders defaults to the headers requested by the preflight request
+	AllowHeaders     []string `yaml:"allow_headers,omitempty" json:"allow_headers,omitempty"`
+	ExposeHeaders    []string `yaml:"expose_headers,omitempty" json:"expose_headers,omitempty"`
+	AllowCredentials bool     `yaml:"allow_credentials,omitempty" json:"allow_credentials,omitempty"`
+	// MaxAge is how long, in seconds, the preflight response can be cached
+	MaxAge int `yaml:"max_age,omitempty" json:"max_age,omitempty"`
+	// PreflightOnly answers the preflight requests, without decorating the other responses, like auto_cors did
+	PreflightOnly bool `yaml:"preflight_only,omitempty" json:"preflight_only,omitempty"`
+}
+
+func (c CORS) Validate() error {
+	return validation.ValidateStruct(
+		&c,
+		validation.Field(&c.AllowMethods, validation.Each(validation.By(isHTTPMethod))),
+		validation.Field(&c.MaxAge, validation.Min(0)),
+	)
+}
+
+// AllowedOrigin returns the value of the Access-Control-Allow-Origin header for the origin, false if it is not allowed
+func (c CORS) AllowedOrigin(origin string) (string, bool) {
+	if len(c.AllowOrigins) == 0 {
+		if c.AllowCredentials {
+			// the wildcard is not accepted by the browsers along with credentials
+			return origin, true
+		}
+		return "*", true
+	}
+
+	for _, allowed := range c.AllowOrigins {
+		if allowed == "*" && !c.AllowCredentials {
+			return "*", true
+		}
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return origin, true
+		}
+	}
+
+	return "", false
+}
+
+// PreflightHeaders returns the headers of the response to the preflight request
+func (c CORS) PreflightHeaders(r *http.Request) http.Header {
+	headers := c.ResponseHeaders(r)
+	if headers.Get("Access-Control-Allow-Origin") == "" {
+		return headers
+	}
+
+	methods := c.AllowMethods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+	headers.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+
+	if len(c.AllowHeaders) > 0 {
+		headers.Set("Access-Control-Allow-Headers", strings.Join(c.AllowHeaders, ", "))
+	} else if requested := r.Header.Get("Access-Control-Request-Headers"); requested != "" {
+		headers.Set("Access-Control-Allow-Headers", requested)
+	}
+
+	if c.MaxAge > 0 {
+		headers.Set("Access-Control-Max-Age", strconv.Itoa(c.MaxAge))
+	}
+
+	return headers
+}
+
+// ResponseHeaders returns the headers added to the response of a cross origin request
+func (c CORS) ResponseHeaders(r *http.Request) http.Header {
+	headers := http.Header{}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return headers
+	}
+
+	allowed, ok := c.AllowedOrigin(origin)
+	if !ok {
+		return headers
+	}
+
+	headers.Set("Access-Control-Allow-Origin", allowed)
+	if allowed != "*" {
+		headers.Add("Vary", "Origin")
+	}
+	if c.AllowCredentials {
+		headers.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if len(c.ExposeHeaders) > 0 {
+		headers.Set("Access-Control-Expose-Headers", strings.Join(c.ExposeHeaders, ", "))
+	}
+
+	return headers
+}
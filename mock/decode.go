@@ -0,0 +1,244 @@
+package mock
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type format string
+
+const (
+	formatYAML format = "yaml"
+	formatJSON format = "json"
+)
+
+// yaml 1.1 booleans, which the yaml decoder still accepts in boolean fields
+var yaml11Bools = map[string]bool{
+	"y": true, "yes": true, "on": true, "true": true,
+	"n": true, "no": true, "off": true, "false": true,
+}
+
+// decode decodes the mock document. The includes are resolved, the document is migrated to the current version
+// and the environment variables are replaced first, then the document is checked against the mock structure, so that the errors can be located in the files
+func decode(src source, file string, data []byte, f format, opts ...Option) (*Mock, error) {
+	m := New(opts...)
+
+	root, err := parseNode(data, f)
+	if err != nil {
+		return nil, Errors{toDecodeError(file, bytes.TrimPrefix(data, utf8BOM), err)}
+	}
+	if root == nil {
+		return nil, Errors{{File: file, Message: "the mock is empty"}}
+	}
+
+	doc := &document{root: root, origins: map[*yaml.Node]origin{}, origin: origin{file: file, format: f}}
+	r := &resolver{src: src, origins: doc.origins, stack: []string{file}}
+	r.resolve(root, reflect.TypeOf(m).Elem(), doc.origin, "")
+	if len(r.errs) > 0 {
+		r.errs.sort()
+		return nil, r.errs
+	}
+
+	if _, err := migrate(root, doc.origin); err != nil {
+		return nil, err
+	}
+
+	for _, key := range []string{"port", "proxy"} {
+		if node := child(root, key); node != nil {
+			r.interpolate(node, doc.origin, key)
+		}
+	}
+	if len(r.errs) > 0 {
+		r.errs.sort()
+		return nil, r.errs
+	}
+
+	c := &checker{origins: doc.origins, origin: doc.origin, strict: m.options.strictDecoding}
+	c.check(root, reflect.TypeOf(m).Elem(), "")
+	if len(c.errs) > 0 {
+		c.errs.sort()
+		return nil, c.errs
+	}
+
+	if err := root.Decode(m); err != nil {
+		return nil, Errors{toDecodeError(file, data, err)}
+	}
+	m.Normalize()
+
+	if m.options.validation {
+		if err := m.Validate(); err != nil {
+			return nil, toErrors(doc, err)
+		}
+	}
+
+	return m, nil
+}
+
+// checker walks the document along the mock structure, it reports the values of the wrong type
+// and, when it is strict, the unknown fields
+type checker struct {
+	origins map[*yaml.Node]origin
+	origin  origin
+	strict  bool
+	errs    Errors
+}
+
+// numericStrings are the string fields which accept the integers of the JSON documents too, like YAML does,
+// the ports and the request numbers are often written as numbers
+var numericStrings = map[string]bool{"Mock.port": true, "Rule.value": true, "Rule.multi_value": true}
+
+func (c *checker) report(node *yaml.Node, path, format string, args ...interface{}) {
+	c.errs = append(c.errs, Error{
+		File:    c.origin.file,
+		Line:    node.Line,
+		Column:  node.Column,
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *checker) check(node *yaml.Node, t reflect.Type, path string) {
+	node = resolve(node)
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!null" {
+		return
+	}
+
+	if included, ok := c.origins[node]; ok {
+		parent := c.origin
+		c.origin = included
+		defer func() { c.origin = parent }()
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			c.report(node, path, "must be an object")
+			return
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "<<" && c.origin.format == formatYAML {
+				c.checkMerge(value, t, path)
+				continue
+			}
+
+			field, ok := fields[key.Value]
+			if !ok {
+				if c.strict {
+					c.report(key, joinPath(path, key.Value), "unknown field %q", key.Value)
+				}
+				continue
+			}
+			if numericStrings[t.Name()+"."+key.Value] && c.isInteger(resolve(value)) {
+				continue
+			}
+			c.check(value, field.Type, joinPath(path, key.Value))
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			c.report(node, path, "must be an object")
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			elem := t.Elem()
+			// a header with a single value can be a string
+			if t == headersType && resolve(node.Content[i+1]).Kind == yaml.ScalarNode {
+				elem = elem.Elem()
+			}
+			c.check(node.Content[i+1], elem, joinPath(path, node.Content[i].Value))
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			c.report(node, path, "must be a list")
+			return
+		}
+		for i, item := range node.Content {
+			c.check(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+	case reflect.String:
+		if node.Kind != yaml.ScalarNode || c.origin.format == formatJSON && node.ShortTag() != "!!str" {
+			c.report(node, path, "must be a string")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if node.Kind != yaml.ScalarNode || !c.isInteger(node) {
+			c.report(node, path, "must be an integer")
+		}
+	case reflect.Bool:
+		if node.Kind != yaml.ScalarNode || !c.isBool(node) {
+			c.report(node, path, "must be a boolean")
+		}
+	}
+}
+
+func (c *checker) checkMerge(node *yaml.Node, t reflect.Type, path string) {
+	node = resolve(node)
+	if node.Kind == yaml.SequenceNode {
+		for _, item := range node.Content {
+			c.check(item, t, path)
+		}
+		return
+	}
+	c.check(node, t, path)
+}
+
+func (c *checker) isBool(node *yaml.Node) bool {
+	if node.ShortTag() == "!!bool" {
+		return true
+	}
+
+	return c.origin.format == formatYAML && node.Style == 0 && yaml11Bools[strings.ToLower(node.Value)]
+}
+
+func (c *checker) isInteger(node *yaml.Node) bool {
+	switch {
+	case node.ShortTag() == "!!int":
+		return true
+	case node.ShortTag() == "!!float" && c.origin.format == formatYAML:
+		f, err := strconv.ParseFloat(node.Value, 64)
+		return err == nil && f == math.Trunc(f)
+	}
+
+	return false
+}
+
+// yamlFields returns the fields of the struct by their yaml name
+func yamlFields(t reflect.Type) map[string]reflect.StructField {
+	fields := map[string]reflect.StructField{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field
+	}
+
+	return fields
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
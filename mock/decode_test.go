@@ -0,0 +1,145 @@
+package mock_test
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/mockingio/engine/mock"
+)
+
+func TestStrictDecoding(t *testing.T) {
+	t.Run("unknown fields are ignored by default", func(t *testing.T) {
+		mock, err := FromFile("fixtures/mock_invalid.yml")
+		require.NoError(t, err)
+		assert.Empty(t, mock.Routes[0].Responses)
+	})
+
+	t.Run("unknown fields are rejected, with their location", func(t *testing.T) {
+		mock, err := FromFile("fixtures/mock_invalid.yml", WithStrictDecoding())
+		assert.Nil(t, mock)
+
+		var errs Errors
+		require.True(t, errors.As(err, &errs))
+		assert.Equal(t, Errors{
+			{File: "fixtures/mock_invalid.yml", Line: 5, Column: 5, Path: "routes[0].respones", Message: `unknown field "respones"`},
+			{File: "fixtures/mock_invalid.yml", Line: 15, Column: 13, Path: "routes[1].responses[0].rules[0].operater", Message: `unknown field "operater"`},
+		}, errs)
+		assert.Equal(t, "fixtures/mock_invalid.yml:5:5: routes[0].respones: unknown field \"respones\"\n"+
+			"fixtures/mock_invalid.yml:15:13: routes[1].responses[0].rules[0].operater: unknown field \"operater\"", err.Error())
+	})
+
+	t.Run("unknown fields are rejected in JSON", func(t *testing.T) {
+		_, err := FromJSON(`{
+  "routes": [{"path": "/hello", "respones": []}]
+}`, WithStrictDecoding())
+
+		assert.Equal(t, Errors{
+			{Line: 2, Column: 33, Path: "routes[0].respones", Message: `unknown field "respones"`},
+		}, err)
+	})
+
+	t.Run("values of the wrong type are located", func(t *testing.T) {
+		_, err := FromYaml(`routes:
+  - path: /hello
+    responses:
+      - status: ok
+        delay: 10
+        headers: [a, b]
+`)
+
+		assert.Equal(t, Errors{
+			{Line: 4, Column: 17, Path: "routes[0].responses[0].status", Message: "must be an integer"},
+			{Line: 6, Column: 18, Path: "routes[0].responses[0].headers", Message: "must be an object"},
+		}, err)
+	})
+
+	t.Run("JSON values must have the exact type", func(t *testing.T) {
+		_, err := FromJSON(`{"auto_cors": "yes", "routes": [{"path": 1}]}`)
+
+		assert.Equal(t, Errors{
+			{Line: 1, Column: 15, Path: "auto_cors", Message: "must be a boolean"},
+			{Line: 1, Column: 42, Path: "routes[0].path", Message: "must be a string"},
+		}, err)
+	})
+
+	t.Run("the port and the rule values accept JSON integers, like the schema", func(t *testing.T) {
+		mock, err := FromJSON(`{"port": 8080, "routes": [{"path": "/users", "responses": [{"status": 200, "rules": [
+			{"target": "request_number", "value": 2, "operator": "equal"},
+			{"target": "header", "modifier": "X-Id", "value": "1", "multi_value": 0, "operator": "equal"}
+		]}]}]}`, WithValidation())
+		require.NoError(t, err)
+
+		assert.Equal(t, "8080", mock.Port)
+		assert.Equal(t, "2", mock.Routes[0].Responses[0].Rules[0].Value)
+		assert.Equal(t, "0", mock.Routes[0].Responses[0].Rules[1].MultiValue)
+	})
+
+	t.Run("syntax errors are located", func(t *testing.T) {
+		_, err := FromJSON("{\n  \"routes\": [\n}")
+
+		var errs Errors
+		require.True(t, errors.As(err, &errs))
+		require.Len(t, errs, 1)
+		assert.Equal(t, 3, errs[0].Line)
+		assert.Equal(t, 2, errs[0].Column)
+	})
+
+	t.Run("yaml 1.1 booleans and aliases are accepted", func(t *testing.T) {
+		mock, err := FromYaml(`auto_cors: yes
+defaults: &ok
+  status: 200
+routes:
+  - path: /hello
+    responses:
+      - *ok
+`)
+		require.NoError(t, err)
+		assert.True(t, mock.CORS.Enabled)
+		assert.Equal(t, 200, mock.Routes[0].Responses[0].Status)
+	})
+}
+
+func TestValidationErrors(t *testing.T) {
+	t.Run("validation errors are located", func(t *testing.T) {
+		_, err := FromYaml(`routes:
+  - path: /hello
+    response_mode: loop
+    responses:
+      - status: 200
+        rule_aggregation: xor
+`, WithValidation())
+
+		assert.Equal(t, Errors{
+			{Line: 3, Column: 20, Path: "routes[0].response_mode", Message: "must be a valid value"},
+			{Line: 6, Column: 27, Path: "routes[0].responses[0].rule_aggregation", Message: "must be a valid value"},
+		}, err)
+	})
+
+	t.Run("missing fields are located at their parent", func(t *testing.T) {
+		_, err := FromJSON(`{"name": "empty"}`, WithValidation())
+
+		assert.Equal(t, Errors{
+			{Line: 1, Column: 1, Path: "routes", Message: "cannot be blank"},
+		}, err)
+	})
+
+	t.Run("valid mock", func(t *testing.T) {
+		mock, err := FromFile("fixtures/mock.json", WithValidation(), WithStrictDecoding())
+		require.NoError(t, err)
+		assert.Len(t, mock.Routes, 4)
+	})
+
+	t.Run("the body of a route is not a field", func(t *testing.T) {
+		_, err := FromFile("fixtures/mock.yml", WithStrictDecoding())
+		assert.EqualError(t, err, `fixtures/mock.yml:56:5: routes[3].body: unknown field "body"`)
+	})
+}
+
+func TestError(t *testing.T) {
+	assert.Equal(t, "mock.yml:3:5: routes[0].path: cannot be blank", Error{File: "mock.yml", Line: 3, Column: 5, Path: "routes[0].path", Message: "cannot be blank"}.Error())
+	assert.Equal(t, "routes: cannot be blank", Error{Path: "routes", Message: "cannot be blank"}.Error())
+	assert.Equal(t, "EOF", Error{Message: "EOF"}.Error())
+}
@@ -0,0 +1,410 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	includeKeys = map[string]bool{"$ref": true, "include": true}
+
+	// ${NAME} or ${NAME:-default}, the default is used when the variable is unset or empty
+	envRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+)
+
+// source reads the mock files, from the disk or from a fs.FS
+type source interface {
+	ReadFile(name string) ([]byte, error)
+	Glob(pattern string) ([]string, error)
+	// Join returns the name of a file referenced by the parent file
+	Join(parent, name string) string
+}
+
+type osSource struct{}
+
+func (osSource) ReadFile(name string) ([]byte, error) {
+	return ioutil.ReadFile(name)
+}
+
+func (osSource) Glob(pattern string) ([]string, error) {
+	return filepath.Glob(pattern)
+}
+
+func (osSource) Join(parent, name string) string {
+	if filepath.IsAbs(name) || parent == "" {
+		return filepath.Clean(name)
+	}
+	return filepath.Join(filepath.Dir(parent), name)
+}
+
+type fsSource struct {
+	fsys fs.FS
+}
+
+func (s fsSource) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(s.fsys, name)
+}
+
+func (s fsSource) Glob(pattern string) ([]string, error) {
+	return fs.Glob(s.fsys, pattern)
+}
+
+func (fsSource) Join(parent, name string) string {
+	if parent == "" {
+		return path.Clean(name)
+	}
+	return path.Join(path.Dir(parent), name)
+}
+
+// origin is the file a node comes from
+type origin struct {
+	file   string
+	format format
+}
+
+// document is a parsed mock file, with the files it includes
+type document struct {
+	root *yaml.Node
+	// origins keeps the file of the included nodes, the other nodes come from the file of their parent
+	origins map[*yaml.Node]origin
+	origin  origin
+}
+
+func formatOf(name string, data []byte) format {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return formatJSON
+	case ".yml", ".yaml":
+		return formatYAML
+	}
+
+	return detectFormat(data)
+}
+
+// parseNode parses the document to a yaml node, the node is nil if the document is empty
+func parseNode(data []byte, f format) (*yaml.Node, error) {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	if f == formatJSON {
+		return parseJSON(data)
+	}
+
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	return doc.Content[0], nil
+}
+
+// parseJSON parses the JSON document to a yaml node. The yaml parser is not used because JSON is
+// not exactly a subset of YAML, escapes like \/ are not valid in YAML
+func parseJSON(data []byte) (*yaml.Node, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	p := &jsonParser{data: data, decoder: decoder}
+
+	node, err := p.value()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, &json.SyntaxError{Offset: p.start()}
+	}
+
+	return node, nil
+}
+
+type jsonParser struct {
+	data    []byte
+	decoder *json.Decoder
+}
+
+// start returns the offset of the next token
+func (p *jsonParser) start() int64 {
+	offset := p.decoder.InputOffset()
+	for offset < int64(len(p.data)) && strings.IndexByte(" \t\r\n,:", p.data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+func (p *jsonParser) value() (*yaml.Node, error) {
+	line, column := position(p.data, p.start())
+	token, err := p.decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	node := &yaml.Node{Line: line, Column: column}
+	switch t := token.(type) {
+	case json.Delim:
+		node.Style = yaml.FlowStyle
+		node.Kind, node.Tag = yaml.SequenceNode, "!!seq"
+		if t == '{' {
+			node.Kind, node.Tag = yaml.MappingNode, "!!map"
+		}
+		for p.decoder.More() {
+			child, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, child)
+		}
+		if _, err := p.decoder.Token(); err != nil {
+			return nil, err
+		}
+	case string:
+		node.Kind, node.Tag, node.Value, node.Style = yaml.ScalarNode, "!!str", t, yaml.DoubleQuotedStyle
+	case json.Number:
+		node.Kind, node.Tag, node.Value = yaml.ScalarNode, "!!float", t.String()
+		if _, err := t.Int64(); err == nil {
+			node.Tag = "!!int"
+		}
+	case bool:
+		node.Kind, node.Tag, node.Value = yaml.ScalarNode, "!!bool", fmt.Sprint(t)
+	case nil:
+		node.Kind, node.Tag, node.Value = yaml.ScalarNode, "!!null", "null"
+	}
+
+	return node, nil
+}
+
+// resolver replaces the {$ref: file} and {include: file} directives by the content of the files.
+// The files are parsed, unless they are included in a string field like a body.
+// In a list, the directive can match several files, and the lists they contain are spliced
+type resolver struct {
+	src     source
+	origins map[*yaml.Node]origin
+	stack   []string
+	errs    Errors
+}
+
+func (r *resolver) report(node *yaml.Node, o origin, path, format string, args ...interface{}) {
+	r.errs = append(r.errs, Error{
+		File:    o.file,
+		Line:    node.Line,
+		Column:  node.Column,
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// directive returns the file referenced by the node, if it is an include directive
+func directive(node *yaml.Node) (string, bool) {
+	if node.Kind != yaml.MappingNode || len(node.Content) != 2 {
+		return "", false
+	}
+
+	key, value := node.Content[0], node.Content[1]
+	if !includeKeys[key.Value] || value.Kind != yaml.ScalarNode {
+		return "", false
+	}
+
+	return value.Value, true
+}
+
+func (r *resolver) resolve(node *yaml.Node, t reflect.Type, o origin, path string) {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	if included, ok := r.origins[node]; ok {
+		o = included
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if file, ok := directive(node); ok {
+		r.include(node, t, o, path, file)
+		return
+	}
+
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "<<" {
+				r.resolve(value, t, o, path)
+				continue
+			}
+			if field, ok := fields[key.Value]; ok {
+				r.resolve(value, field.Type, o, joinPath(path, key.Value))
+			}
+		}
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			r.resolve(node.Content[i+1], t.Elem(), o, joinPath(path, node.Content[i].Value))
+		}
+	case t.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		var items []*yaml.Node
+		for i, item := range node.Content {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			if file, ok := directive(item); ok {
+				items = append(items, r.includeItems(item, t.Elem(), o, itemPath, file)...)
+				continue
+			}
+			r.resolve(item, t.Elem(), o, itemPath)
+			items = append(items, item)
+		}
+		node.Content = items
+	}
+}
+
+// include replaces the directive node by the content of the file
+func (r *resolver) include(node *yaml.Node, t reflect.Type, o origin, path, file string) {
+	name := r.src.Join(o.file, file)
+
+	if t.Kind() == reflect.String {
+		data, err := r.src.ReadFile(name)
+		if err != nil {
+			r.report(node, o, path, "include %s: %v", file, err)
+			return
+		}
+		*node = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: string(data), Style: yaml.LiteralStyle, Line: node.Line, Column: node.Column}
+		return
+	}
+
+	included, ok := r.load(node, o, path, name)
+	if !ok {
+		return
+	}
+
+	r.stack = append(r.stack, name)
+	r.resolve(included.root, t, included.origin, path)
+	r.stack = r.stack[:len(r.stack)-1]
+
+	*node = *included.root
+	r.origins[node] = included.origin
+}
+
+// includeItems returns the items of a list included from the files matching the pattern
+func (r *resolver) includeItems(node *yaml.Node, t reflect.Type, o origin, path, pattern string) []*yaml.Node {
+	names, err := r.src.Glob(r.src.Join(o.file, pattern))
+	if err == nil && len(names) == 0 {
+		err = fs.ErrNotExist
+	}
+	if err != nil {
+		r.report(node, o, path, "include %s: %v", pattern, err)
+		return nil
+	}
+	sort.Strings(names)
+
+	var items []*yaml.Node
+	for _, name := range names {
+		included, ok := r.load(node, o, path, name)
+		if !ok {
+			continue
+		}
+
+		nodes := []*yaml.Node{included.root}
+		if included.root.Kind == yaml.SequenceNode {
+			nodes = included.root.Content
+		}
+
+		r.stack = append(r.stack, name)
+		for _, item := range nodes {
+			r.origins[item] = included.origin
+			r.resolve(item, t, included.origin, path)
+		}
+		r.stack = r.stack[:len(r.stack)-1]
+
+		items = append(items, nodes...)
+	}
+
+	return items
+}
+
+// load parses an included file, the error is reported at the directive
+func (r *resolver) load(node *yaml.Node, o origin, path, name string) (*document, bool) {
+	for i, parent := range r.stack {
+		if parent == name {
+			cycle := append(append([]string(nil), r.stack[i:]...), name)
+			r.report(node, o, path, "include cycle %s", strings.Join(cycle, " -> "))
+			return nil, false
+		}
+	}
+
+	data, err := r.src.ReadFile(name)
+	if err != nil {
+		r.report(node, o, path, "include %s: %v", name, err)
+		return nil, false
+	}
+
+	f := formatOf(name, data)
+	root, err := parseNode(data, f)
+	if err != nil {
+		r.errs = append(r.errs, toDecodeError(name, data, err))
+		return nil, false
+	}
+	if root == nil {
+		r.report(node, o, path, "include %s: the file is empty", name)
+		return nil, false
+	}
+
+	return &document{root: root, origin: origin{file: name, format: f}}, true
+}
+
+// interpolate replaces the environment variables in the scalars of the node
+func (r *resolver) interpolate(node *yaml.Node, o origin, path string) {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	if included, ok := r.origins[node]; ok {
+		o = included
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			r.interpolate(node.Content[i+1], o, joinPath(path, node.Content[i].Value))
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			r.interpolate(item, o, fmt.Sprintf("%s[%d]", path, i))
+		}
+	case yaml.ScalarNode:
+		if !strings.Contains(node.Value, "${") {
+			return
+		}
+
+		node.Value = envRegex.ReplaceAllStringFunc(node.Value, func(variable string) string {
+			match := envRegex.FindStringSubmatch(variable)
+			value, ok := os.LookupEnv(match[1])
+			switch {
+			case ok && (value != "" || match[2] == ""):
+				return value
+			case match[2] != "":
+				return match[3]
+			default:
+				r.report(node, o, path, "environment variable %s is not set", match[1])
+				return ""
+			}
+		})
+
+		// the value is resolved again, so that a plain ${PORT:-8080} becomes an integer
+		if node.Style == 0 {
+			node.Tag = ""
+		}
+	}
+}
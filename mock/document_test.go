@@ -0,0 +1,112 @@
+package mock_test
+
+import (
+	"embed"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/mockingio/engine/mock"
+)
+
+//go:embed fixtures/include
+var includeFixtures embed.FS
+
+func TestIncludes(t *testing.T) {
+	t.Run("routes, responses and bodies are included relative to the parent file", func(t *testing.T) {
+		mock, err := FromFile("fixtures/include/mock.yml", WithValidation(), WithStrictDecoding())
+		require.NoError(t, err)
+
+		require.Len(t, mock.Routes, 4)
+		assert.Equal(t, "/orders", mock.Routes[0].Path)
+		assert.Equal(t, "[]\n", mock.Routes[0].Responses[0].Body)
+		assert.Equal(t, "/users/:id", mock.Routes[1].Path)
+		assert.Equal(t, "{\"id\": 1, \"name\": \"John Doe\"}\n", mock.Routes[1].Responses[0].Body)
+		assert.Equal(t, "DELETE", mock.Routes[2].Method)
+		assert.Equal(t, Response{
+			Status:  200,
+			Headers: map[string][]string{"Content-Type": {"application/json"}},
+			Body:    `{"status": "up", "path": "/health"}`,
+		}, mock.Routes[3].Responses[0])
+	})
+
+	t.Run("load from fs.FS", func(t *testing.T) {
+		expected, err := FromFile("fixtures/include/mock.yml")
+		require.NoError(t, err)
+
+		mock, err := FromFS(includeFixtures, "fixtures/include/mock.yml")
+		require.NoError(t, err)
+		assert.Equal(t, expected, mock)
+	})
+
+	t.Run("include cycle", func(t *testing.T) {
+		_, err := FromFile("fixtures/include/cycle/a.yml")
+		assert.EqualError(t, err, "fixtures/include/cycle/b.yml:4:5: routes[0].responses[0]: "+
+			"include cycle fixtures/include/cycle/a.yml -> fixtures/include/cycle/b.yml -> fixtures/include/cycle/a.yml")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := FromFS(includeFixtures, "fixtures/include/missing.yml")
+		assert.EqualError(t, err, "fixtures/include/missing.yml:7:11: routes[0].responses[0].body: "+
+			"include bodies/missing.json: open fixtures/include/bodies/missing.json: file does not exist")
+	})
+
+	t.Run("errors are located in the included file", func(t *testing.T) {
+		_, err := FromFile("fixtures/include/typo.yml", WithStrictDecoding())
+		assert.EqualError(t, err, `fixtures/include/routes/typo.yml.txt:3:1: routes[0].respones: unknown field "respones"`)
+	})
+}
+
+func TestInterpolation(t *testing.T) {
+	t.Run("defaults are used when the variables are not set", func(t *testing.T) {
+		mock, err := FromFile("fixtures/include/mock.yml")
+		require.NoError(t, err)
+
+		assert.Equal(t, "8080", mock.Port)
+		assert.Equal(t, "https://shop.example.com", mock.Proxy.Host)
+		assert.Equal(t, "Bearer local", mock.Proxy.RequestHeaders["Authorization"])
+	})
+
+	t.Run("variables are replaced", func(t *testing.T) {
+		t.Setenv("SHOP_PORT", "9090")
+		t.Setenv("SHOP_UPSTREAM", "https://staging.shop.example.com")
+		t.Setenv("SHOP_TOKEN", "secret")
+
+		mock, err := FromFile("fixtures/include/mock.yml")
+		require.NoError(t, err)
+
+		assert.Equal(t, "9090", mock.Port)
+		assert.Equal(t, "https://staging.shop.example.com", mock.Proxy.Host)
+		assert.Equal(t, "Bearer secret", mock.Proxy.RequestHeaders["Authorization"])
+	})
+
+	t.Run("boolean values", func(t *testing.T) {
+		t.Setenv("PROXY_ENABLED", "false")
+
+		mock, err := FromYaml(`proxy:
+  enabled: ${PROXY_ENABLED:-true}
+routes:
+  - path: /hello
+    responses:
+      - status: 200
+`)
+		require.NoError(t, err)
+		assert.False(t, mock.Proxy.Enabled)
+	})
+
+	t.Run("variable without a default is not set", func(t *testing.T) {
+		_, err := FromJSON(`{"port": "${UNSET_MOCK_PORT}", "routes": []}`)
+		assert.EqualError(t, err, "1:10: port: environment variable UNSET_MOCK_PORT is not set")
+	})
+
+	t.Run("only the port and the proxy are interpolated", func(t *testing.T) {
+		mock, err := FromYaml(`routes:
+  - path: /hello
+    responses:
+      - body: ${NAME}
+`)
+		require.NoError(t, err)
+		assert.Equal(t, "${NAME}", mock.Routes[0].Responses[0].Body)
+	})
+}
@@ -0,0 +1,200 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"gopkg.in/yaml.v3"
+)
+
+var yamlLineRegex = regexp.MustCompile(`^yaml: (?:unmarshal errors:\s*)?line (\d+): (.*)$`)
+
+// Error is a decoding or validation error, located in the mock file
+type Error struct {
+	File    string `yaml:"file,omitempty" json:"file,omitempty"`
+	Line    int    `yaml:"line,omitempty" json:"line,omitempty"`
+	Column  int    `yaml:"column,omitempty" json:"column,omitempty"`
+	Path    string `yaml:"path,omitempty" json:"path,omitempty"`
+	Message string `yaml:"message" json:"message"`
+}
+
+// Error formats the error like compilers do, mock.yml:12:9: routes[3].responses[0].rules[1].operator: must be a valid value
+func (e Error) Error() string {
+	var location []string
+	if e.File != "" {
+		location = append(location, e.File)
+	}
+	if e.Line > 0 {
+		location = append(location, strconv.Itoa(e.Line))
+		if e.Column > 0 {
+			location = append(location, strconv.Itoa(e.Column))
+		}
+	}
+
+	var parts []string
+	if len(location) > 0 {
+		parts = append(parts, strings.Join(location, ":"))
+	}
+	if e.Path != "" {
+		parts = append(parts, e.Path)
+	}
+
+	return strings.Join(append(parts, e.Message), ": ")
+}
+
+// Errors are the errors found in a mock file, in the order of the file
+type Errors []Error
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+func (e Errors) sort() {
+	sort.SliceStable(e, func(i, j int) bool {
+		if e[i].Line != e[j].Line {
+			return e[i].Line < e[j].Line
+		}
+		return e[i].Column < e[j].Column
+	})
+}
+
+// toErrors locates the validation errors in the document, the errors are keyed by the json name of the fields
+func toErrors(doc *document, err error) Errors {
+	var errs Errors
+	var walk func(keys []string, err error)
+	walk = func(keys []string, err error) {
+		if nested, ok := err.(validation.Errors); ok {
+			for key, e := range nested {
+				walk(append(append([]string(nil), keys...), key), e)
+			}
+			return
+		}
+
+		node, o := doc.lookup(keys)
+		e := Error{File: o.file, Path: toPath(keys), Message: err.Error(), Line: node.Line, Column: node.Column}
+		errs = append(errs, e)
+	}
+	walk(nil, err)
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Path < errs[j].Path
+	})
+	errs.sort()
+
+	return errs
+}
+
+// toPath joins the keys to a path like routes[3].responses[0].rules[1].operator
+func toPath(keys []string) string {
+	var path strings.Builder
+	for _, key := range keys {
+		if _, err := strconv.Atoi(key); err == nil {
+			path.WriteString("[" + key + "]")
+			continue
+		}
+		if path.Len() > 0 {
+			path.WriteString(".")
+		}
+		path.WriteString(key)
+	}
+
+	return path.String()
+}
+
+// lookup returns the node at the keys and its file, or the closest parent when the field is missing
+func (d *document) lookup(keys []string) (*yaml.Node, origin) {
+	node, o := d.root, d.origin
+	for _, key := range keys {
+		next := child(node, key)
+		if next == nil {
+			break
+		}
+		node = next
+		if included, ok := d.origins[node]; ok {
+			o = included
+		}
+	}
+
+	return node, o
+}
+
+func child(node *yaml.Node, key string) *yaml.Node {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				return resolve(node.Content[i+1])
+			}
+		}
+	case yaml.SequenceNode:
+		if idx, err := strconv.Atoi(key); err == nil && idx >= 0 && idx < len(node.Content) {
+			return resolve(node.Content[idx])
+		}
+	}
+
+	return nil
+}
+
+// resolve returns the content of a document, and the node an alias points to
+func resolve(node *yaml.Node) *yaml.Node {
+	for {
+		switch {
+		case node.Kind == yaml.DocumentNode && len(node.Content) > 0:
+			node = node.Content[0]
+		case node.Kind == yaml.AliasNode && node.Alias != nil:
+			node = node.Alias
+		default:
+			return node
+		}
+	}
+}
+
+// toDecodeError locates the error returned by the yaml or json decoder
+func toDecodeError(file string, data []byte, err error) Error {
+	e := Error{File: file, Message: err.Error()}
+
+	switch err := err.(type) {
+	case *json.SyntaxError:
+		e.Line, e.Column = position(data, err.Offset)
+	case *json.UnmarshalTypeError:
+		e.Line, e.Column = position(data, err.Offset)
+		e.Path = err.Field
+		e.Message = fmt.Sprintf("must be %s", err.Type)
+	default:
+		if match := yamlLineRegex.FindStringSubmatch(strings.TrimSpace(err.Error())); match != nil {
+			e.Line, _ = strconv.Atoi(match[1])
+			e.Message = match[2]
+		}
+	}
+
+	return e
+}
+
+// position converts an offset in the data to a line and a column, both starting at 1
+func position(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+
+	line, column := 1, 1
+	for _, b := range data[:offset] {
+		if b == '\n' {
+			line++
+			column = 1
+			continue
+		}
+		column++
+	}
+
+	return line, column
+}
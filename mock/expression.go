@@ -0,0 +1,101 @@
+package mock
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/pkg/errors"
+)
+
+// expressionCostLimit stops the expressions which would take too long to evaluate, like nested comprehensions on big bodies
+const expressionCostLimit = 1000000
+
+var (
+	expressionEnv     *cel.Env
+	expressionEnvErr  error
+	expressionEnvOnce sync.Once
+)
+
+// compiledExpression is a compiled expression, along with the expression it was compiled from
+type compiledExpression struct {
+	expression string
+	program    cel.Program
+	err        error
+}
+
+// ExpressionVariables are the variables of the request an expression can use. The headers are keyed by their
+// canonical and their lower case names, the session has its id and the states of the scenarios, by name
+var ExpressionVariables = map[string]*cel.Type{
+	"method":         cel.StringType,
+	"path":           cel.StringType,
+	"params":         cel.MapType(cel.StringType, cel.StringType),
+	"query":          cel.MapType(cel.StringType, cel.StringType),
+	"headers":        cel.MapType(cel.StringType, cel.StringType),
+	"cookies":        cel.MapType(cel.StringType, cel.StringType),
+	"body":           cel.DynType,
+	"request_number": cel.IntType,
+	"session":        cel.MapType(cel.StringType, cel.DynType),
+}
+
+func newExpressionEnv() (*cel.Env, error) {
+	opts := []cel.EnvOption{cel.CrossTypeNumericComparisons(true)}
+	for name, t := range ExpressionVariables {
+		opts = append(opts, cel.Variable(name, t))
+	}
+
+	return cel.NewEnv(opts...)
+}
+
+// CompileExpression compiles a CEL expression evaluated against the request, the expression must return a boolean.
+// The result is not cached, the rules keep their compiled expression, see Rule.Program
+func CompileExpression(expression string) (cel.Program, error) {
+	expressionEnvOnce.Do(func() {
+		expressionEnv, expressionEnvErr = newExpressionEnv()
+	})
+	if expressionEnvErr != nil {
+		return nil, errors.Wrap(expressionEnvErr, "create expression environment")
+	}
+
+	ast, issues := expressionEnv.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+
+	// a dynamic result, like a field of the body, is checked when the expression is evaluated
+	if t := ast.OutputType(); !t.IsAssignableType(cel.BoolType) {
+		return nil, fmt.Errorf("must return a bool, not %s", t)
+	}
+
+	return expressionEnv.Program(ast, cel.CostLimit(expressionCostLimit))
+}
+
+// Program returns the compiled expression of the rule.
+// The expression compiled when the mock was normalized is reused, unless the rule was changed since
+func (r Rule) Program() (cel.Program, error) {
+	if c := r.compiled; c != nil && c.expression == r.Value {
+		return c.program, c.err
+	}
+	return CompileExpression(r.Value)
+}
+
+// compileExpressions compiles the expressions of the mock once, instead of for each request. The errors are reported by Validate
+func compileExpressions(m *Mock) {
+	for _, route := range m.Routes {
+		for _, res := range route.Responses {
+			res.RuleGroup().walkRules(func(rule *Rule, _ []interface{}) {
+				if rule.Target == Expression {
+					program, err := CompileExpression(rule.Value)
+					rule.compiled = &compiledExpression{expression: rule.Value, program: program, err: err}
+				}
+			})
+		}
+	}
+}
+
+func (r Rule) isExpression(_ interface{}) error {
+	if _, err := r.Program(); err != nil {
+		return fmt.Errorf("must be a valid expression: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package mock
+
+import (
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// Fallback is the response written when a request matches none of the routes
+type Fallback struct {
+	Status  int     `yaml:"status,omitempty" json:"status,omitempty"`
+	Headers Headers `yaml:"headers,omitempty" json:"headers,omitempty"`
+	Body    string  `yaml:"body,omitempty" json:"body,omitempty"`
+	// Body is rendered as a text/template with the request and the closest routes if Template is true
+	Template bool `yaml:"template,omitempty" json:"template,omitempty"`
+	// Strict responds with 501 and an explanation of why the request was not matched
+	Strict bool `yaml:"strict,omitempty" json:"strict,omitempty"`
+}
+
+func (f Fallback) Validate() error {
+	return validation.ValidateStruct(
+		&f,
+		validation.Field(&f.Status, validation.When(f.Status != 0, validation.Min(100), validation.Max(599))),
+		validation.Field(&f.Body, validation.When(f.Template, validation.By(isTemplate))),
+	)
+}
+
+// StatusCode returns the status of the fallback response, 404 if it is not set
+func (f Fallback) StatusCode() int {
+	if f.Strict {
+		return http.StatusNotImplemented
+	}
+
+	if f.Status == 0 {
+		return http.StatusNotFound
+	}
+
+	return f.Status
+}
+
+// RenderBody returns the body, executed as a template against data if Template is true
+func (f Fallback) RenderBody(data interface{}) ([]byte, error) {
+	if !f.Template {
+		return []byte(f.Body), nil
+	}
+
+	return renderTemplate(f.Body, data)
+}
@@ -0,0 +1,54 @@
+package mock
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFallback_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		fallback Fallback
+		error    bool
+	}{
+		{"empty fallback", Fallback{}, false},
+		{"valid status", Fallback{Status: http.StatusTeapot}, false},
+		{"invalid status", Fallback{Status: 1000}, true},
+		{"valid template", Fallback{Body: `{{ json .ClosestRoutes }}`, Template: true}, false},
+		{"invalid template", Fallback{Body: `{{ .Request`, Template: true}, true},
+		{"invalid template, not templated", Fallback{Body: `{{ .Request`}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.fallback.Validate()
+			assert.Equal(t, tt.error, err != nil)
+		})
+	}
+}
+
+func TestFallback_StatusCode(t *testing.T) {
+	assert.Equal(t, http.StatusNotFound, Fallback{}.StatusCode())
+	assert.Equal(t, http.StatusTeapot, Fallback{Status: http.StatusTeapot}.StatusCode())
+	assert.Equal(t, http.StatusNotImplemented, Fallback{Status: http.StatusTeapot, Strict: true}.StatusCode())
+}
+
+func TestFallback_RenderBody(t *testing.T) {
+	body, err := Fallback{Body: "{{ .Name }}"}.RenderBody(map[string]string{"Name": "joe"})
+	require.NoError(t, err)
+	assert.Equal(t, "{{ .Name }}", string(body))
+
+	body, err = Fallback{Body: "{{ .Name }}", Template: true}.RenderBody(map[string]string{"Name": "joe"})
+	require.NoError(t, err)
+	assert.Equal(t, "joe", string(body))
+
+	body, err = Fallback{Body: `<faultstring>No route for {{ xml .Path }}</faultstring>`, Template: true}.RenderBody(map[string]string{"Path": `/stock?a=1&b="2"`})
+	require.NoError(t, err)
+	assert.Equal(t, `<faultstring>No route for /stock?a=1&amp;b=&#34;2&#34;</faultstring>`, string(body))
+
+	_, err = Fallback{Body: "{{ .Name", Template: true}.RenderBody(nil)
+	assert.Error(t, err)
+}
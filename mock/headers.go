@@ -0,0 +1,80 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+var headersType = reflect.TypeOf(Headers{})
+
+// Headers are the headers of a response, a header is written once for each of its values.
+// A header with a single value can be a string instead of a list, like Content-Type: text/plain
+type Headers map[string][]string
+
+// UnmarshalJSON accepts a string or a list of strings for each header
+func (h *Headers) UnmarshalJSON(data []byte) error {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	if values == nil {
+		*h = nil
+		return nil
+	}
+
+	headers := make(Headers, len(values))
+	for name, raw := range values {
+		var value string
+		if err := json.Unmarshal(raw, &value); err == nil {
+			if string(raw) != "null" {
+				headers[name] = []string{value}
+			} else {
+				headers[name] = nil
+			}
+			continue
+		}
+
+		var list []string
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return fmt.Errorf("header %s must be a string or a list of strings", name)
+		}
+		headers[name] = list
+	}
+
+	*h = headers
+	return nil
+}
+
+// UnmarshalYAML accepts a string or a list of strings for each header
+func (h *Headers) UnmarshalYAML(node *yaml.Node) error {
+	node = resolve(node)
+	if node.Kind != yaml.MappingNode {
+		var values map[string][]string
+		if err := node.Decode(&values); err != nil {
+			return err
+		}
+		*h = values
+		return nil
+	}
+
+	headers := make(Headers, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		name, value := node.Content[i].Value, resolve(node.Content[i+1])
+		if value.Kind == yaml.ScalarNode && value.ShortTag() != "!!null" {
+			headers[name] = []string{value.Value}
+			continue
+		}
+
+		var list []string
+		if err := value.Decode(&list); err != nil {
+			return err
+		}
+		headers[name] = list
+	}
+
+	*h = headers
+	return nil
+}
@@ -0,0 +1,507 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the version of the mock format, the documents without a version are at version 1
+const CurrentVersion = 3
+
+type migration struct {
+	description string
+	// migrate upgrades an object of the document, it is called with each object and its type. It returns true if the object changed
+	migrate func(node *yaml.Node, t reflect.Type) bool
+}
+
+// migrations upgrade the documents from a version to the next one, they are applied step by step
+var migrations = map[int]migration{
+	1: {"auto_cors becomes a cors policy", migrateAutoCORS},
+	2: {"the response headers become lists of values", migrateResponseHeaders},
+}
+
+var (
+	mockType     = reflect.TypeOf(Mock{})
+	responseType = reflect.TypeOf(Response{})
+	fallbackType = reflect.TypeOf(Fallback{})
+)
+
+// migrate upgrades the document to the current version, it returns the descriptions of the applied migrations
+func migrate(root *yaml.Node, o origin) ([]string, error) {
+	version, err := documentVersion(root, o)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []string
+	for v := version; v < CurrentVersion; v++ {
+		applyMigration(root, mockType, migrations[v])
+		applied = append(applied, fmt.Sprintf("%d to %d: %s", v, v+1, migrations[v].description))
+	}
+	if len(applied) > 0 {
+		setField(root, "version", scalar("!!int", strconv.Itoa(CurrentVersion)), true)
+	}
+
+	return applied, nil
+}
+
+// documentVersion returns the version of the mock document, 1 if it is not set, and CurrentVersion for a document
+// which is not a mock, like a file included at the top
+func documentVersion(root *yaml.Node, o origin) (int, error) {
+	if root.Kind != yaml.MappingNode {
+		return CurrentVersion, nil
+	}
+
+	node := child(root, "version")
+	if node == nil {
+		return 1, nil
+	}
+
+	version, err := strconv.Atoi(node.Value)
+	if err != nil || version < 1 {
+		return 0, Errors{{File: o.file, Line: node.Line, Column: node.Column, Path: "version", Message: "must be a positive integer"}}
+	}
+
+	if version > CurrentVersion {
+		return 0, Errors{{
+			File:    o.file,
+			Line:    node.Line,
+			Column:  node.Column,
+			Path:    "version",
+			Message: fmt.Sprintf("version %d is newer than the supported version %d", version, CurrentVersion),
+		}}
+	}
+
+	return version, nil
+}
+
+// applyMigration applies the migration to each object of the document, it returns true if the document changed
+func applyMigration(root *yaml.Node, t reflect.Type, m migration) bool {
+	changed := false
+	w := &walker{visit: func(node *yaml.Node, t reflect.Type) {
+		if m.migrate(node, t) {
+			changed = true
+		}
+	}}
+	w.walk(root, t)
+	return changed
+}
+
+// inclusion is an include directive found by the walker, the included documents are of the type
+type inclusion struct {
+	node *yaml.Node
+	file string
+	t    reflect.Type
+	// items is true for a directive in a list, it matches several files whose lists are spliced
+	items bool
+}
+
+// walker walks the document along the mock structure. The include directives are not followed, they are collected
+type walker struct {
+	visit      func(node *yaml.Node, t reflect.Type)
+	inclusions []inclusion
+}
+
+func (w *walker) walk(node *yaml.Node, t reflect.Type) {
+	node = resolve(node)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if file, ok := directive(node); ok {
+		// the files included in the strings, like the bodies, are not mock documents
+		if t.Kind() != reflect.String {
+			w.inclusions = append(w.inclusions, inclusion{node: node, file: file, t: t})
+		}
+		return
+	}
+
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		if w.visit != nil {
+			w.visit(node, t)
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "<<" {
+				w.walk(value, t)
+				continue
+			}
+			if field, ok := fields[key.Value]; ok {
+				w.walk(value, field.Type)
+			}
+		}
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			w.walk(node.Content[i], t.Elem())
+		}
+	case t.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for _, item := range node.Content {
+			if file, ok := directive(item); ok {
+				w.inclusions = append(w.inclusions, inclusion{node: item, file: file, t: t.Elem(), items: true})
+				continue
+			}
+			w.walk(item, t.Elem())
+		}
+	}
+}
+
+// migrateAutoCORS replaces auto_cors: true by cors: {enabled: true, preflight_only: true}, so that only the preflight
+// requests are answered, as before. An invalid value is left to the validation
+func migrateAutoCORS(root *yaml.Node, t reflect.Type) bool {
+	if t != mockType {
+		return false
+	}
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "auto_cors" {
+			continue
+		}
+
+		enabled, ok := boolValue(root.Content[i+1])
+		if !ok {
+			return false
+		}
+
+		if !enabled || child(root, "cors") != nil {
+			root.Content = append(root.Content[:i], root.Content[i+2:]...)
+			return true
+		}
+
+		// the key is renamed, so that the field keeps its place and its comments
+		root.Content[i].Value = "cors"
+		root.Content[i+1] = &yaml.Node{
+			Kind: yaml.MappingNode,
+			Tag:  "!!map",
+			Content: []*yaml.Node{
+				scalar("!!str", "enabled"), scalar("!!bool", "true"),
+				scalar("!!str", "preflight_only"), scalar("!!bool", "true"),
+			},
+		}
+		return true
+	}
+
+	return false
+}
+
+// migrateResponseHeaders replaces the single values of the response and fallback headers by lists, so that a header,
+// like Set-Cookie, can be written several times. The values which are not scalars are left to the decoding
+func migrateResponseHeaders(response *yaml.Node, t reflect.Type) bool {
+	if t != responseType && t != fallbackType {
+		return false
+	}
+
+	headers := child(response, "headers")
+	if headers == nil || headers.Kind != yaml.MappingNode {
+		return false
+	}
+
+	changed := false
+	for i := 1; i < len(headers.Content); i += 2 {
+		value := resolve(headers.Content[i])
+		if value.Kind != yaml.ScalarNode || value.ShortTag() == "!!null" {
+			continue
+		}
+		// the lists are written inline, X-Tier: [gold], so that the headers keep their shape and their comments
+		list := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle, Content: []*yaml.Node{value}}
+		list.LineComment, value.LineComment = value.LineComment, ""
+		headers.Content[i] = list
+		changed = true
+	}
+
+	return changed
+}
+
+// boolValue returns the value of a boolean scalar, yaml 1.1 booleans like yes are accepted
+func boolValue(node *yaml.Node) (bool, bool) {
+	if node.Kind != yaml.ScalarNode {
+		return false, false
+	}
+
+	switch strings.ToLower(node.Value) {
+	case "true", "y", "yes", "on":
+		return true, node.ShortTag() == "!!bool" || node.Style == 0
+	case "false", "n", "no", "off":
+		return false, node.ShortTag() == "!!bool" || node.Style == 0
+	}
+
+	return false, false
+}
+
+func scalar(tag, value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+}
+
+// setField sets the value of the field of the mapping, a new field is added first or last
+func setField(node *yaml.Node, key string, value *yaml.Node, first bool) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = value
+			return
+		}
+	}
+
+	field := []*yaml.Node{scalar("!!str", key), value}
+	if first {
+		// the comment at the top of the document stays on top
+		if len(node.Content) > 0 {
+			field[0].HeadComment, node.Content[0].HeadComment = node.Content[0].HeadComment, ""
+		}
+		node.Content = append(field, node.Content...)
+		return
+	}
+	node.Content = append(node.Content, field...)
+}
+
+// Migrate upgrades a YAML or JSON mock document to the current version. The order of the fields,
+// and the comments of a YAML document are kept. It returns the descriptions of the applied migrations,
+// the document is returned as is when it is up to date. The included files are not migrated, see MigrateFile
+func Migrate(data []byte) ([]byte, []string, error) {
+	f := detectFormat(data)
+	root, err := parseNode(data, f)
+	if err != nil {
+		return nil, nil, Errors{toDecodeError("", bytes.TrimPrefix(data, utf8BOM), err)}
+	}
+	if root == nil {
+		return data, nil, nil
+	}
+
+	applied, err := migrate(root, origin{format: f})
+	if err != nil || len(applied) == 0 {
+		return data, nil, err
+	}
+
+	out, err := encodeNode(root, f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return out, applied, nil
+}
+
+// MigratedFile is a mock file, or a file it includes, upgraded to the current version
+type MigratedFile struct {
+	Name string
+	Data []byte
+	// Applied are the descriptions of the migrations which changed the file
+	Applied []string
+}
+
+// migratedDocument is a document of the mock being migrated, the included documents are of the type
+type migratedDocument struct {
+	doc     *document
+	t       reflect.Type
+	applied []string
+}
+
+// MigrateFile upgrades the mock file, and the files it includes with $ref or include, to the current version.
+// The included files are at the version of the mock which includes them, like when the mock is loaded.
+// Only the files which changed are returned, the mock file first
+func MigrateFile(file string) ([]MigratedFile, error) {
+	return migrateFiles(osSource{}, file)
+}
+
+func migrateFiles(src source, file string) ([]MigratedFile, error) {
+	data, err := src.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "read mock file")
+	}
+
+	f := formatOf(file, data)
+	root, err := parseNode(data, f)
+	if err != nil {
+		return nil, Errors{toDecodeError(file, bytes.TrimPrefix(data, utf8BOM), err)}
+	}
+	if root == nil {
+		return nil, nil
+	}
+
+	mok := &document{root: root, origin: origin{file: file, format: f}}
+	version, err := documentVersion(root, mok.origin)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := loadInclusions(src, &migratedDocument{doc: mok, t: mockType})
+	if err != nil {
+		return nil, err
+	}
+
+	for v := version; v < CurrentVersion; v++ {
+		description := fmt.Sprintf("%d to %d: %s", v, v+1, migrations[v].description)
+		for i, d := range docs {
+			// the version of the mock changes, even if the migration has nothing to change
+			if applyMigration(d.doc.root, d.t, migrations[v]) || i == 0 {
+				d.applied = append(d.applied, description)
+			}
+		}
+	}
+	if version < CurrentVersion {
+		setField(root, "version", scalar("!!int", strconv.Itoa(CurrentVersion)), true)
+	}
+
+	var files []MigratedFile
+	for _, d := range docs {
+		if len(d.applied) == 0 {
+			continue
+		}
+		out, err := encodeNode(d.doc.root, d.doc.origin.format)
+		if err != nil {
+			return nil, errors.Wrapf(err, "encode %s", d.doc.origin.file)
+		}
+		files = append(files, MigratedFile{Name: d.doc.origin.file, Data: out, Applied: d.applied})
+	}
+
+	return files, nil
+}
+
+// loadInclusions returns the mock document and the documents it includes, each file is loaded once
+func loadInclusions(src source, mok *migratedDocument) ([]*migratedDocument, error) {
+	docs := []*migratedDocument{mok}
+	loaded := map[string]bool{mok.doc.origin.file: true}
+
+	for i := 0; i < len(docs); i++ {
+		parent := docs[i]
+		w := &walker{}
+		w.walk(parent.doc.root, parent.t)
+
+		for _, inc := range w.inclusions {
+			names := []string{src.Join(parent.doc.origin.file, inc.file)}
+			if inc.items {
+				matches, err := src.Glob(names[0])
+				if err != nil {
+					return nil, Errors{{File: parent.doc.origin.file, Line: inc.node.Line, Column: inc.node.Column, Message: fmt.Sprintf("include %s: %v", inc.file, err)}}
+				}
+				sort.Strings(matches)
+				names = matches
+			}
+
+			for _, name := range names {
+				if loaded[name] {
+					continue
+				}
+				loaded[name] = true
+
+				data, err := src.ReadFile(name)
+				if err != nil {
+					return nil, Errors{{File: parent.doc.origin.file, Line: inc.node.Line, Column: inc.node.Column, Message: fmt.Sprintf("include %s: %v", inc.file, err)}}
+				}
+				f := formatOf(name, data)
+				root, err := parseNode(data, f)
+				if err != nil {
+					return nil, Errors{toDecodeError(name, data, err)}
+				}
+				if root == nil {
+					continue
+				}
+
+				// a file included in a list can hold a list of items
+				t := inc.t
+				if inc.items && root.Kind == yaml.SequenceNode {
+					t = reflect.SliceOf(t)
+				}
+				docs = append(docs, &migratedDocument{doc: &document{root: root, origin: origin{file: name, format: f}}, t: t})
+			}
+		}
+	}
+
+	return docs, nil
+}
+
+// encodeNode writes the document in its format, the JSON documents are indented with two spaces
+func encodeNode(root *yaml.Node, f format) ([]byte, error) {
+	if f == formatJSON {
+		var buf bytes.Buffer
+		if err := writeJSON(&buf, root); err != nil {
+			return nil, err
+		}
+		var out bytes.Buffer
+		if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+			return nil, err
+		}
+		out.WriteString("\n")
+		return out.Bytes(), nil
+	}
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// writeJSON writes the node as JSON, the fields are kept in order
+func writeJSON(buf *bytes.Buffer, node *yaml.Node) error {
+	node = resolve(node)
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		buf.WriteString("{")
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			if err := writeJSONValue(buf, node.Content[i].Value); err != nil {
+				return err
+			}
+			buf.WriteString(":")
+			if err := writeJSON(buf, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("}")
+	case yaml.SequenceNode:
+		buf.WriteString("[")
+		for i, item := range node.Content {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			if err := writeJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("]")
+	default:
+		switch tag := node.ShortTag(); {
+		case tag == "!!str":
+			return writeJSONValue(buf, node.Value)
+		case (tag == "!!int" || tag == "!!float") && json.Valid([]byte(node.Value)):
+			buf.WriteString(node.Value)
+			return nil
+		}
+
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return err
+		}
+		return writeJSONValue(buf, value)
+	}
+
+	return nil
+}
+
+// writeJSONValue writes the value without escaping the HTML characters, which are common in bodies
+func writeJSONValue(buf *bytes.Buffer, value interface{}) error {
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+	buf.Truncate(buf.Len() - 1)
+	return nil
+}
@@ -0,0 +1,142 @@
+package mock_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/mockingio/engine/mock"
+)
+
+func TestMigrate(t *testing.T) {
+	t.Run("documents without a version are migrated when they are loaded", func(t *testing.T) {
+		mock, err := FromYaml(`auto_cors: true
+routes:
+  - path: /hello
+    responses:
+      - status: 200
+`)
+		require.NoError(t, err)
+		assert.Equal(t, CurrentVersion, mock.Version)
+		assert.False(t, mock.AutoCORS)
+		assert.Equal(t, &CORS{Enabled: true, PreflightOnly: true}, mock.CORS)
+	})
+
+	t.Run("documents at the current version are not migrated", func(t *testing.T) {
+		mock, err := FromYaml(`version: 3
+auto_cors: true
+routes:
+  - path: /hello
+    responses:
+      - status: 200
+`)
+		require.NoError(t, err)
+		assert.True(t, mock.AutoCORS)
+		assert.Nil(t, mock.CORS)
+		assert.NotNil(t, mock.CORSPolicy())
+	})
+
+	t.Run("the single values of the response headers become lists", func(t *testing.T) {
+		mock, err := FromYaml(`version: 2
+routes:
+  - path: /hello
+    responses:
+      - status: 200
+        headers:
+          Content-Type: text/plain
+          Set-Cookie: [a=1, b=2]
+`)
+		require.NoError(t, err)
+		assert.Equal(t, Headers{
+			"Content-Type": {"text/plain"},
+			"Set-Cookie":   {"a=1", "b=2"},
+		}, mock.Routes[0].Responses[0].Headers)
+	})
+
+	t.Run("the headers with a single value are accepted at the current version", func(t *testing.T) {
+		mock, err := FromJSON(`{
+  "version": 3,
+  "routes": [{"path": "/hello", "responses": [{"status": 200, "headers": {"Content-Type": "text/plain"}}]}],
+  "fallback": {"headers": {"Content-Type": "text/plain", "Set-Cookie": ["a=1", "b=2"]}}
+}`)
+		require.NoError(t, err)
+		assert.Equal(t, Headers{"Content-Type": {"text/plain"}}, mock.Routes[0].Responses[0].Headers)
+		assert.Equal(t, Headers{"Content-Type": {"text/plain"}, "Set-Cookie": {"a=1", "b=2"}}, mock.Fallback.Headers)
+
+		_, err = FromJSON(`{"version": 3, "routes": [{"path": "/", "responses": [{"status": 200, "headers": {"X-Count": 1}}]}]}`)
+		assert.EqualError(t, err, "1:94: routes[0].responses[0].headers.X-Count: must be a string")
+	})
+
+	t.Run("the single values of the fallback headers become lists", func(t *testing.T) {
+		data, applied, err := Migrate([]byte(`version: 2
+routes:
+  - path: /hello
+fallback:
+  headers:
+    Content-Type: text/plain
+`))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2 to 3: the response headers become lists of values"}, applied)
+		assert.Equal(t, `version: 3
+routes:
+  - path: /hello
+fallback:
+  headers:
+    Content-Type: [text/plain]
+`, string(data))
+	})
+
+	t.Run("documents newer than the supported version", func(t *testing.T) {
+		_, err := FromJSON(`{"version": 4, "routes": []}`)
+		assert.EqualError(t, err, "1:13: version: version 4 is newer than the supported version 3")
+	})
+
+	t.Run("migrate a JSON document, the order of the fields is kept", func(t *testing.T) {
+		data, applied, err := Migrate([]byte(`{"name": "<Shop>", "auto_cors": true, "routes": [{"path": "\/hello", "responses": [{"status": 200, "delay": 1.5e3, "headers": {"X-Id": 1}}]}]}`))
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"1 to 2: auto_cors becomes a cors policy",
+			"2 to 3: the response headers become lists of values",
+		}, applied)
+		assert.Equal(t, `{
+  "version": 3,
+  "name": "<Shop>",
+  "cors": {
+    "enabled": true,
+    "preflight_only": true
+  },
+  "routes": [
+    {
+      "path": "/hello",
+      "responses": [
+        {
+          "status": 200,
+          "delay": 1.5e3,
+          "headers": {
+            "X-Id": [
+              1
+            ]
+          }
+        }
+      ]
+    }
+  ]
+}
+`, string(data))
+	})
+
+	t.Run("disabled auto_cors is removed", func(t *testing.T) {
+		data, _, err := Migrate([]byte("name: Shop\nauto_cors: false\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "version: 3\nname: Shop\n", string(data))
+	})
+
+	t.Run("up to date documents are returned as is", func(t *testing.T) {
+		document := []byte("version: 3\nname:   Shop\n")
+		data, applied, err := Migrate(document)
+		require.NoError(t, err)
+		assert.Empty(t, applied)
+		assert.Equal(t, document, data)
+	})
+}
@@ -1,25 +1,43 @@
 package mock
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
 	"io/ioutil"
 	"net/http"
-	"strings"
+	"regexp"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
-	"gopkg.in/yaml.v2"
 )
 
 type Mock struct {
-	ID     string   `yaml:"id,omitempty" json:"id,omitempty"`
-	Name   string   `yaml:"name,omitempty" json:"name,omitempty"`
-	Port   string   `yaml:"port,omitempty" json:"port,omitempty"`
-	Routes []*Route `yaml:"routes,omitempty" json:"routes,omitempty"`
-	Proxy  *Proxy   `yaml:"proxy,omitempty" json:"proxy,omitempty"`
+	// Version is the version of the mock format, the older documents are migrated when they are loaded
+	Version int      `yaml:"version,omitempty" json:"version,omitempty"`
+	ID      string   `yaml:"id,omitempty" json:"id,omitempty"`
+	Name    string   `yaml:"name,omitempty" json:"name,omitempty"`
+	Port    string   `yaml:"port,omitempty" json:"port,omitempty"`
+	Routes  []*Route `yaml:"routes,omitempty" json:"routes,omitempty"`
+	Proxy   *Proxy   `yaml:"proxy,omitempty" json:"proxy,omitempty"`
 	// all OPTIONS calls are responded with success if AutoCORS is true
+	//
+	// Deprecated: use CORS, auto_cors is migrated to a CORS policy when the mock is loaded
 	AutoCORS bool `yaml:"auto_cors,omitempty" json:"auto_cors,omitempty"`
-	options  mockOptions
+	// CORS answers the preflight requests which match no route, and decorates the cross origin responses
+	CORS *CORS `yaml:"cors,omitempty" json:"cors,omitempty"`
+	// Fallback is used to respond requests which match no route, an empty 404 is written if it is not set
+	Fallback *Fallback `yaml:"fallback,omitempty" json:"fallback,omitempty"`
+	// Namespaces are the XML namespaces of the XPath of the xml_body rules by their prefix,
+	// the prefixes of the request body do not matter
+	Namespaces map[string]string `yaml:"namespaces,omitempty" json:"namespaces,omitempty"`
+	// BasePath prefixes the paths of all the routes, like /v1, so that the mock can be mounted under another path
+	BasePath string `yaml:"base_path,omitempty" json:"base_path,omitempty"`
+	// PathOptions control how the paths of the requests are matched to the route paths
+	PathOptions *PathOptions `yaml:"path_options,omitempty" json:"path_options,omitempty"`
+	options     mockOptions
 }
 
 func New(opts ...Option) *Mock {
@@ -34,44 +52,139 @@ func New(opts ...Option) *Mock {
 	return m
 }
 
+var basePathRegex = regexp.MustCompile(`^/[^?#]*$`)
+
+// utf8BOM is written at the start of the files by some editors, and is not valid JSON
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// FromFile loads a YAML or JSON mock file, the format is detected by the extension, or by the content otherwise.
+// The files included by the mock are resolved relative to it
 func FromFile(file string, opts ...Option) (*Mock, error) {
-	// TODO: Detects file type, support JSON
 	data, err := ioutil.ReadFile(file)
 	if err != nil {
 		return nil, errors.Wrap(err, "read mock file")
 	}
 
-	return FromYaml(string(data), opts...)
+	return decode(osSource{}, file, data, formatOf(file, data), opts...)
+}
+
+// FromFS loads a YAML or JSON mock file from the file system, like the files embedded with go:embed
+func FromFS(fsys fs.FS, name string, opts ...Option) (*Mock, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "read mock file")
+	}
+
+	return decode(fsSource{fsys: fsys}, name, data, formatOf(name, data), opts...)
+}
+
+// FromReader loads a YAML or JSON mock, the format is detected by the content
+func FromReader(r io.Reader, opts ...Option) (*Mock, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "read mock")
+	}
+
+	return decode(osSource{}, "", data, detectFormat(data), opts...)
 }
 
 func FromYaml(text string, opts ...Option) (*Mock, error) {
-	decoder := yaml.NewDecoder(strings.NewReader(text))
-	m := New(opts...)
-	if err := decoder.Decode(m); err != nil {
-		return nil, errors.Wrap(err, "decode yaml to mock")
+	return decode(osSource{}, "", []byte(text), formatYAML, opts...)
+}
+
+func FromJSON(text string, opts ...Option) (*Mock, error) {
+	return decode(osSource{}, "", []byte(text), formatJSON, opts...)
+}
+
+// Normalize fills the default values, and generates the missing IDs when the mock is created WithIDGeneration
+func (m *Mock) Normalize() {
+	if m.Version == 0 {
+		m.Version = CurrentVersion
 	}
 	defaultValues(m)
 	if m.options.idGeneration {
 		addIDs(m)
 	}
+	compilePaths(m)
+	compileExpressions(m)
+}
 
-	return m, nil
+// compilePaths compiles the paths of the routes once, instead of for each request
+func compilePaths(m *Mock) {
+	for _, r := range m.Routes {
+		pattern, err := r.compilePath()
+		r.compiled = &compiledPath{path: r.Path, mode: r.PathMode, pattern: pattern, err: err}
+	}
 }
 
+// Validate validates the whole mock, all the problems are reported at once
 func (m Mock) Validate() error {
-	return validation.ValidateStruct(
+	errs, err := toValidationErrors(validation.ValidateStruct(
 		&m,
+		validation.Field(&m.Version, validation.Min(0), validation.Max(CurrentVersion)),
 		validation.Field(&m.Routes, validation.Required),
-	)
+		validation.Field(&m.CORS),
+		validation.Field(&m.Fallback),
+		validation.Field(&m.Namespaces, validation.Each(validation.Required)),
+		validation.Field(&m.BasePath, validation.Match(basePathRegex).Error("must start with a slash, like /v1")),
+		validation.Field(&m.PathOptions),
+	))
+	if err != nil {
+		return err
+	}
+
+	m.validateIDs(errs)
+
+	return errs.Filter()
+}
+
+// validateIDs reports the IDs which are used more than once
+func (m Mock) validateIDs(errs validation.Errors) {
+	seen := map[string]string{}
+	check := func(id string, keys ...interface{}) {
+		if id == "" {
+			return
+		}
+		path := toPath(toKeys(keys))
+		if first, ok := seen[id]; ok {
+			addError(errs, fmt.Errorf("must be unique, %s is already used by %s", id, first), keys...)
+			return
+		}
+		seen[id] = path
+	}
+
+	check(m.ID, "id")
+	for i, r := range m.Routes {
+		check(r.ID, "routes", i, "id")
+		for j, res := range r.Responses {
+			check(res.ID, "routes", i, "responses", j, "id")
+			res.RuleGroup().walkRules(func(rule *Rule, keys []interface{}) {
+				check(rule.ID, appendKeys(keys, "id")...)
+			}, "routes", i, "responses", j)
+		}
+	}
 }
 
 func (m Mock) ProxyEnabled() bool {
 	return m.Proxy != nil && m.Proxy.Enabled
 }
 
+// CORSPolicy returns the enabled CORS policy, AutoCORS enables the default policy for the preflight requests only
+func (m Mock) CORSPolicy() *CORS {
+	if m.CORS != nil && m.CORS.Enabled {
+		return m.CORS
+	}
+
+	if m.AutoCORS {
+		return &CORS{Enabled: true, PreflightOnly: true}
+	}
+
+	return nil
+}
+
 func defaultValues(m *Mock) {
 	for _, r := range m.Routes {
-		if r.Method == "" {
+		if r.Method == "" && len(r.Methods) == 0 {
 			r.Method = http.MethodGet
 		}
 		for i, res := range r.Responses {
@@ -99,16 +212,25 @@ func addIDs(m *Mock) {
 				r.Responses[i] = res
 			}
 
-			for j, rule := range res.Rules {
+			res.RuleGroup().walkRules(func(rule *Rule, _ []interface{}) {
 				if rule.ID == "" {
 					rule.ID = newID()
-					res.Rules[j] = rule
 				}
-			}
+			})
 		}
 	}
 }
 
+// detectFormat detects JSON documents by their opening brace, a YAML mock never starts with one
+func detectFormat(data []byte) format {
+	data = bytes.TrimSpace(bytes.TrimPrefix(data, utf8BOM))
+	if len(data) > 0 && data[0] == '{' {
+		return formatJSON
+	}
+
+	return formatYAML
+}
+
 func newID() string {
 	return uuid.NewString()
 }
@@ -1,8 +1,12 @@
 package mock_test
 
 import (
+	"bytes"
 	_ "embed"
+	"encoding/json"
+	"io/ioutil"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"gopkg.in/yaml.v2"
@@ -46,6 +50,89 @@ func TestConfig(t *testing.T) {
 		assert.Equal(t, 200, mock.Routes[0].Responses[0].Status)
 	})
 
+	t.Run("Load mock from JSON file, same as the YAML file", func(t *testing.T) {
+		fromYaml, err := FromFile("fixtures/mock.yml")
+		require.NoError(t, err)
+
+		fromJSON, err := FromFile("fixtures/mock.json")
+		require.NoError(t, err)
+
+		assert.Equal(t, fromYaml, fromJSON)
+	})
+
+	t.Run("Load mock from JSON file, with ID generation option", func(t *testing.T) {
+		mock, err := FromFile("fixtures/mock.json", WithIDGeneration())
+		require.NoError(t, err)
+
+		assert.True(t, mock.ID != "")
+		assert.True(t, mock.Routes[0].ID != "")
+		assert.True(t, mock.Routes[0].Responses[0].ID != "")
+		assert.True(t, mock.Routes[0].Responses[0].Rules[0].ID != "")
+	})
+
+	t.Run("Load mock from JSON, use default GET/200 as response", func(t *testing.T) {
+		mock, err := FromJSON(`{"routes": [{"path": "/hello", "responses": [{"body": "world"}]}]}`)
+		require.NoError(t, err)
+
+		assert.Equal(t, "GET", mock.Routes[0].Method)
+		assert.Equal(t, 200, mock.Routes[0].Responses[0].Status)
+	})
+
+	t.Run("Load mock from reader, detect the format by the content", func(t *testing.T) {
+		expected, err := FromFile("fixtures/mock.yml")
+		require.NoError(t, err)
+
+		for _, file := range []string{"fixtures/mock.yml", "fixtures/mock.json"} {
+			data, err := ioutil.ReadFile(file)
+			require.NoError(t, err)
+
+			mock, err := FromReader(bytes.NewReader(data))
+			require.NoError(t, err)
+			assert.Equal(t, expected, mock, file)
+		}
+
+		mock, err := FromReader(strings.NewReader("\xef\xbb\xbf\n  {\"routes\": [{\"path\": \"/hello\"}]}"))
+		require.NoError(t, err)
+		assert.Equal(t, "/hello", mock.Routes[0].Path)
+	})
+
+	t.Run("Load mock from file without extension, detect the format by the content", func(t *testing.T) {
+		expected, err := FromFile("fixtures/mock.json")
+		require.NoError(t, err)
+
+		data, err := ioutil.ReadFile("fixtures/mock.json")
+		require.NoError(t, err)
+
+		file := filepath.Join(t.TempDir(), "mock")
+		require.NoError(t, ioutil.WriteFile(file, data, 0o600))
+
+		mock, err := FromFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, expected, mock)
+	})
+
+	t.Run("JSON round trip", func(t *testing.T) {
+		expected, err := FromFile("fixtures/mock.yml")
+		require.NoError(t, err)
+
+		data, err := json.Marshal(expected)
+		require.NoError(t, err)
+
+		mock, err := FromJSON(string(data))
+		require.NoError(t, err)
+		assert.Equal(t, expected, mock)
+	})
+
+	t.Run("error loading mock from invalid json", func(t *testing.T) {
+		mock, err := FromJSON(`{"routes": [`)
+		assert.Error(t, err)
+		assert.Nil(t, mock)
+
+		mock, err = FromJSON("")
+		assert.Error(t, err)
+		assert.Nil(t, mock)
+	})
+
 	t.Run("error loading config from YAML file", func(t *testing.T) {
 		mock, err := FromFile("")
 		assert.Error(t, err)
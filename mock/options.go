@@ -1,7 +1,9 @@
 package mock
 
 type mockOptions struct {
-	idGeneration bool
+	idGeneration   bool
+	strictDecoding bool
+	validation     bool
 }
 
 type Option func(*mockOptions)
@@ -11,3 +13,17 @@ func WithIDGeneration() Option {
 		m.idGeneration = true
 	}
 }
+
+// WithStrictDecoding rejects the unknown fields of the mock file, like a misspelled respones
+func WithStrictDecoding() Option {
+	return func(m *mockOptions) {
+		m.strictDecoding = true
+	}
+}
+
+// WithValidation validates the mock once it is decoded, the errors are located in the mock file
+func WithValidation() Option {
+	return func(m *mockOptions) {
+		m.validation = true
+	}
+}
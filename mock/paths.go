@@ -0,0 +1,36 @@
+package mock
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type trailingSlash string
+
+const (
+	// StrictTrailingSlash does not match a path which differs from the route path by a trailing slash
+	StrictTrailingSlash trailingSlash = ""
+	// IgnoreTrailingSlash matches a path whatever its trailing slash
+	IgnoreTrailingSlash trailingSlash = "ignore"
+	// RedirectTrailingSlash redirects a path which differs from the route path by a trailing slash to the route path
+	RedirectTrailingSlash trailingSlash = "redirect"
+)
+
+var trailingSlashes = []interface{}{StrictTrailingSlash, IgnoreTrailingSlash, RedirectTrailingSlash}
+
+// PathOptions control how the paths of the requests are normalized before they are matched to the route paths
+type PathOptions struct {
+	TrailingSlash trailingSlash `yaml:"trailing_slash,omitempty" json:"trailing_slash,omitempty"`
+	// CaseInsensitive matches the literal parts of the paths whatever their case, the params keep their case
+	CaseInsensitive bool `yaml:"case_insensitive,omitempty" json:"case_insensitive,omitempty"`
+	// MergeSlashes matches the paths with duplicate slashes, like //users, as if they had a single slash
+	MergeSlashes bool `yaml:"merge_slashes,omitempty" json:"merge_slashes,omitempty"`
+	// DecodePath decodes the whole path before it is matched, so that the encoded slashes separate the segments
+	DecodePath bool `yaml:"decode_path,omitempty" json:"decode_path,omitempty"`
+}
+
+func (p PathOptions) Validate() error {
+	return validation.ValidateStruct(
+		&p,
+		validation.Field(&p.TrailingSlash, validation.In(trailingSlashes...)),
+	)
+}
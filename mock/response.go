@@ -9,23 +9,57 @@ type RuleAggregation string
 const (
 	Or  RuleAggregation = "or"
 	And RuleAggregation = "and"
+	Not RuleAggregation = "not"
 )
 
+var ruleAggregations = []interface{}{Or, And, Not}
+
 type Response struct {
-	ID              string            `yaml:"id,omitempty" json:"id,omitempty"`
-	Status          int               `yaml:"status" json:"status"`
-	Delay           int64             `yaml:"delay,omitempty" json:"delay,omitempty"`
-	Headers         map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
-	Body            string            `yaml:"body,omitempty" json:"body,omitempty"`
-	RuleAggregation RuleAggregation   `yaml:"rule_aggregation,omitempty" json:"rule_aggregation,omitempty"`
-	Rules           []Rule            `yaml:"rules,omitempty" json:"rules,omitempty"`
-	IsDefault       bool              `yaml:"is_default,omitempty" json:"is_default,omitempty"`
+	ID      string  `yaml:"id,omitempty" json:"id,omitempty"`
+	Status  int     `yaml:"status" json:"status"`
+	Delay   int64   `yaml:"delay,omitempty" json:"delay,omitempty"`
+	Headers Headers `yaml:"headers,omitempty" json:"headers,omitempty"`
+	Body    string  `yaml:"body,omitempty" json:"body,omitempty"`
+	// Body is rendered as a text/template with the request if Template is true
+	Template        bool            `yaml:"template,omitempty" json:"template,omitempty"`
+	RuleAggregation RuleAggregation `yaml:"rule_aggregation,omitempty" json:"rule_aggregation,omitempty"`
+	Rules           []Rule          `yaml:"rules,omitempty" json:"rules,omitempty"`
+	// RuleGroups are aggregated with the rules, by the rule aggregation of the response
+	RuleGroups []RuleGroup `yaml:"rule_groups,omitempty" json:"rule_groups,omitempty"`
+	IsDefault  bool        `yaml:"is_default,omitempty" json:"is_default,omitempty"`
+	// Scenario is the name of the state machine the required and the new states belong to, its state is kept by session
+	Scenario string `yaml:"scenario,omitempty" json:"scenario,omitempty"`
+	// RequiredState is the state the scenario must be in for the response to match, any state if it is not set
+	RequiredState string `yaml:"required_state,omitempty" json:"required_state,omitempty"`
+	// NewState is the state the scenario moves to when the response is written
+	NewState string `yaml:"new_state,omitempty" json:"new_state,omitempty"`
 }
 
 func (r Response) Validate() error {
 	return validation.ValidateStruct(
 		&r,
 		validation.Field(&r.Status, validation.Required),
-		validation.Field(&r.RuleAggregation, validation.In(Or, And)),
+		validation.Field(&r.Body, validation.When(r.Template, validation.By(isTemplate))),
+		validation.Field(&r.RuleAggregation, validation.In(ruleAggregations...)),
+		validation.Field(&r.Rules),
+		validation.Field(&r.RuleGroups),
+		validation.Field(&r.Scenario, validation.When(
+			r.RequiredState != "" || r.NewState != "",
+			validation.Required.Error("must be set with the required or the new state"),
+		)),
 	)
 }
+
+// RenderBody returns the body, executed as a template against data if Template is true
+func (r Response) RenderBody(data interface{}) ([]byte, error) {
+	if !r.Template {
+		return []byte(r.Body), nil
+	}
+
+	return renderTemplate(r.Body, data)
+}
+
+// RuleGroup returns the rules and the groups of the response as a group
+func (r Response) RuleGroup() RuleGroup {
+	return RuleGroup{RuleAggregation: r.RuleAggregation, Rules: r.Rules, RuleGroups: r.RuleGroups}
+}
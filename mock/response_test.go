@@ -15,6 +15,18 @@ func TestResponse_Validate(t *testing.T) {
 	}{
 		{"valid status 200", Response{Status: http.StatusOK, RuleAggregation: Or}, false},
 		{"invalid status 1000", Response{}, true},
+		{"valid template", Response{Status: http.StatusOK, Template: true, Body: "<id>{{ xml .Request.Path }}</id>"}, false},
+		{"invalid template", Response{Status: http.StatusOK, Template: true, Body: "{{ .Request"}, true},
+		{"not a template", Response{Status: http.StatusOK, Body: "{{ .Request"}, false},
+		{"valid rule groups", Response{Status: http.StatusOK, RuleGroups: []RuleGroup{
+			{RuleAggregation: Not, Rules: []Rule{{Target: Header, Modifier: "Authorization", Operator: Exists}}},
+		}}, false},
+		{"invalid rule groups, empty group", Response{Status: http.StatusOK, RuleGroups: []RuleGroup{
+			{RuleAggregation: And, RuleGroups: []RuleGroup{{RuleAggregation: Or}}},
+		}}, true},
+		{"invalid rule groups, unknown aggregation", Response{Status: http.StatusOK, RuleGroups: []RuleGroup{
+			{RuleAggregation: "xor", Rules: []Rule{{Target: Header, Modifier: "Authorization", Operator: Exists}}},
+		}}, true},
 	}
 
 	for _, tt := range tests {
@@ -1,7 +1,13 @@
 package mock
 
 import (
+	"fmt"
+	"net/http"
+	"strings"
+
 	validation "github.com/go-ozzo/ozzo-validation/v4"
+
+	"github.com/mockingio/engine/pathpattern"
 )
 
 type responseMode string
@@ -12,6 +18,22 @@ const (
 	DefaultResponse      responseMode = ""
 )
 
+var responseModes = []interface{}{DefaultResponse, ResponseRandomly, ResponseSequentially}
+
+type pathMode string
+
+const (
+	// DefaultPathMode matches the path with the params, like /users/:id
+	DefaultPathMode pathMode = ""
+	// PathRegex matches the path with a regex, its named groups are the route params
+	PathRegex pathMode = "regex"
+)
+
+var pathModes = []interface{}{DefaultPathMode, PathRegex}
+
+// AnyMethod is the method of the routes which match all the methods
+const AnyMethod = "ANY"
+
 type Route struct {
 	ID           string       `yaml:"id,omitempty" json:"id,omitempty"`
 	Method       string       `yaml:"method" json:"method"`
@@ -19,13 +41,122 @@ type Route struct {
 	Description  string       `yaml:"description" json:"description"`
 	ResponseMode responseMode `yaml:"response_mode,omitempty" json:"response_mode,omitempty"`
 	Responses    []Response   `yaml:"responses" json:"responses"`
+	// Methods are the methods of a route matching several methods, the method is not set then
+	Methods []string `yaml:"methods,omitempty" json:"methods,omitempty"`
+	// PathMode is how the path is matched, with its params or as a regex
+	PathMode pathMode `yaml:"path_mode,omitempty" json:"path_mode,omitempty"`
+	// Query are the query params the request must have, a param whose value is * can have any value
+	Query map[string]string `yaml:"query,omitempty" json:"query,omitempty"`
+
+	// compiled is the path compiled when the mock is normalized
+	compiled *compiledPath
+}
+
+// compiledPath is a compiled path, along with the path and the mode it was compiled from
+type compiledPath struct {
+	path    string
+	mode    pathMode
+	pattern *pathpattern.Pattern
+	err     error
 }
 
 func (r Route) Validate() error {
-	return validation.ValidateStruct(
+	errs, err := toValidationErrors(validation.ValidateStruct(
 		&r,
-		validation.Field(&r.Path, validation.Required),
-		validation.Field(&r.ResponseMode, validation.In(DefaultResponse, ResponseRandomly, ResponseSequentially)),
+		validation.Field(&r.Method, validation.When(r.Method != "", validation.By(isRouteMethod))),
+		validation.Field(&r.Methods,
+			validation.When(r.Method != "", validation.Empty),
+			validation.Each(validation.By(isRouteMethod)),
+		),
+		validation.Field(&r.Path, validation.Required, validation.By(r.isPath)),
+		validation.Field(&r.PathMode, validation.In(pathModes...)),
+		validation.Field(&r.ResponseMode, validation.In(responseModes...)),
 		validation.Field(&r.Responses, validation.Required),
-	)
+	))
+	if err != nil {
+		return err
+	}
+
+	params := r.pathParams()
+	defaultIdx := -1
+	for i, res := range r.Responses {
+		if res.IsDefault {
+			if defaultIdx >= 0 {
+				addError(errs, fmt.Errorf("must be set on one response only, responses[%d] is already the default", defaultIdx), "responses", i, "is_default")
+			} else {
+				defaultIdx = i
+			}
+		}
+		res.RuleGroup().walkRules(func(rule *Rule, keys []interface{}) {
+			if rule.Target == RouteParam && rule.Modifier != "" && !params[rule.Modifier] {
+				addError(errs, fmt.Errorf("must be a param of the path %s", r.Path), appendKeys(keys, "modifier")...)
+			}
+		}, "responses", i)
+	}
+
+	return errs.Filter()
+}
+
+// AllowedMethods returns the methods of the route in upper case, GET if it is not set
+func (r Route) AllowedMethods() []string {
+	methods := r.Methods
+	if r.Method != "" {
+		methods = []string{r.Method}
+	}
+	if len(methods) == 0 {
+		return []string{http.MethodGet}
+	}
+
+	allowed := make([]string, len(methods))
+	for i, method := range methods {
+		allowed[i] = strings.ToUpper(method)
+	}
+	return allowed
+}
+
+// MatchesMethod returns true if the route accepts the method, the HEAD requests are answered by the GET routes
+func (r Route) MatchesMethod(method string) bool {
+	for _, allowed := range r.AllowedMethods() {
+		if allowed == AnyMethod || strings.EqualFold(allowed, method) {
+			return true
+		}
+		if allowed == http.MethodGet && strings.EqualFold(method, http.MethodHead) {
+			return true
+		}
+	}
+	return false
+}
+
+// CompilePath compiles the path of the route, a regex in the regex path mode.
+// The path compiled when the mock was normalized is reused, unless the route was changed since
+func (r Route) CompilePath() (*pathpattern.Pattern, error) {
+	if c := r.compiled; c != nil && c.path == r.Path && c.mode == r.PathMode {
+		return c.pattern, c.err
+	}
+	return r.compilePath()
+}
+
+func (r Route) compilePath() (*pathpattern.Pattern, error) {
+	if r.PathMode == PathRegex {
+		return pathpattern.CompileRegex(r.Path)
+	}
+	return pathpattern.Compile(r.Path)
+}
+
+func (r Route) isPath(_ interface{}) error {
+	if _, err := r.CompilePath(); err != nil {
+		return fmt.Errorf("must be a valid path: %v", err)
+	}
+	return nil
+}
+
+// pathParams returns the names of the params declared by the path, /users/:id declares id
+func (r Route) pathParams() map[string]bool {
+	params := map[string]bool{}
+	if pattern, err := r.CompilePath(); err == nil {
+		for _, name := range pattern.Params() {
+			params[name] = true
+		}
+	}
+	return params
 }
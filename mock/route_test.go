@@ -9,6 +9,16 @@ import (
 	. "github.com/mockingio/engine/mock"
 )
 
+func TestRoute_MatchesMethod(t *testing.T) {
+	assert.True(t, Route{}.MatchesMethod(http.MethodGet))
+	assert.True(t, Route{}.MatchesMethod(http.MethodHead))
+	assert.False(t, Route{}.MatchesMethod(http.MethodPost))
+	assert.True(t, Route{Method: "any"}.MatchesMethod(http.MethodDelete))
+	assert.True(t, Route{Methods: []string{"put", "PATCH"}}.MatchesMethod(http.MethodPut))
+	assert.False(t, Route{Methods: []string{"put", "PATCH"}}.MatchesMethod(http.MethodHead))
+	assert.Equal(t, []string{"PUT", "PATCH"}, Route{Methods: []string{"put", "PATCH"}}.AllowedMethods())
+}
+
 func TestRoute_Validate(t *testing.T) {
 	validResponse := []Response{{Status: http.StatusOK}}
 
@@ -21,6 +31,23 @@ func TestRoute_Validate(t *testing.T) {
 		{"invalid route, missing request", Route{Responses: validResponse}, true},
 		{"invalid route, missing response", Route{Method: "POST", Path: "/"}, true},
 		{"invalid route, invalid response", Route{Method: "POST", Path: "/", Responses: []Response{}}, true},
+		{"valid route, any method", Route{Method: "any", Path: "/", Responses: validResponse}, false},
+		{"valid route, methods", Route{Methods: []string{"GET", "post"}, Path: "/", Responses: validResponse}, false},
+		{"invalid route, unknown methods", Route{Methods: []string{"GET", "FETCH"}, Path: "/", Responses: validResponse}, true},
+		{"invalid route, method and methods", Route{Method: "GET", Methods: []string{"POST"}, Path: "/", Responses: validResponse}, true},
+		{"invalid route, unknown method", Route{Method: "FETCH", Path: "/", Responses: validResponse}, true},
+		{"invalid route, undeclared route param", Route{Method: "GET", Path: "/users", Responses: []Response{
+			{Status: http.StatusOK, Rules: []Rule{{Target: RouteParam, Modifier: "id", Value: "1", Operator: Equal}}},
+		}}, true},
+		{"valid route, catch-all route param", Route{Method: "GET", Path: `/users/:id(\d+)/files/*path`, Responses: []Response{
+			{Status: http.StatusOK, Rules: []Rule{{Target: RouteParam, Modifier: "path", Value: "a/b", Operator: Equal}}},
+		}}, false},
+		{"valid route, regex path", Route{Method: "GET", Path: `/files/(?P<name>[a-z]+)\.txt`, PathMode: PathRegex, Responses: []Response{
+			{Status: http.StatusOK, Rules: []Rule{{Target: RouteParam, Modifier: "name", Value: "notes", Operator: Equal}}},
+		}}, false},
+		{"invalid route, invalid regex path", Route{Method: "GET", Path: `/files/(?P<name>[a-z]+`, PathMode: PathRegex, Responses: validResponse}, true},
+		{"invalid route, unknown path mode", Route{Method: "GET", Path: "/files", PathMode: "glob", Responses: validResponse}, true},
+		{"invalid route, invalid path", Route{Method: "GET", Path: `/users/:id(\d+`, Responses: validResponse}, true},
 	}
 
 	for _, tt := range tests {
@@ -30,3 +57,30 @@ func TestRoute_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestRoute_CompilePath(t *testing.T) {
+	mok := &Mock{Routes: []*Route{{Path: "/users/:id"}}}
+	mok.Normalize()
+	route := mok.Routes[0]
+
+	first, err := route.CompilePath()
+	assert.NoError(t, err)
+	second, err := route.CompilePath()
+	assert.NoError(t, err)
+	assert.Same(t, first, second)
+
+	route.Path = "/orders/:id"
+	pattern, err := route.CompilePath()
+	assert.NoError(t, err)
+	params, ok := pattern.Match("/orders/1")
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"id": "1"}, params)
+
+	route.PathMode = PathRegex
+	route.Path = "/(?P<id>[0-9]+)"
+	pattern, err = route.CompilePath()
+	assert.NoError(t, err)
+	params, ok = pattern.Match("/2")
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"id": "2"}, params)
+}
@@ -14,11 +14,82 @@ const (
 	Cookie        Target = "cookie"
 	RouteParam    Target = "route_param"
 	RequestNumber Target = "request_number"
+	// Expression evaluates the CEL expression of the value against the request, the operator is not set
+	Expression Target = "expression"
+	Method     Target = "method"
+	Path       Target = "path"
+	Host       Target = "host"
+	Scheme     Target = "scheme"
+	// RemoteIP is the client address, the first address of X-Forwarded-For if the request went through a proxy
+	RemoteIP      Target = "remote_ip"
+	URL           Target = "url"
+	BasicAuthUser Target = "basic_auth_user"
+	// JWTClaim is the claim at the path of the modifier, like org.id, of the bearer token. The token is not verified
+	JWTClaim Target = "jwt_claim"
+	// FormField is the field of the modifier of an application/x-www-form-urlencoded body
+	FormField Target = "form_field"
+	// MultipartField is the value of the field of the modifier of a multipart/form-data body
+	MultipartField Target = "multipart_field"
+	// MultipartFileName, MultipartFileContentType and MultipartFileSize describe the file uploaded in the field of the modifier
+	MultipartFileName        Target = "multipart_file_name"
+	MultipartFileContentType Target = "multipart_file_content_type"
+	MultipartFileSize        Target = "multipart_file_size"
+	// XMLBody is the result of the XPath of the modifier on the XML body, the prefixes are the namespaces of the mock
+	XMLBody Target = "xml_body"
+	// SOAPAction is the SOAPAction header of SOAP 1.1, or the action parameter of the content type of SOAP 1.2
+	SOAPAction Target = "soap_action"
+	// JSONBody is the JSON body, or the JSON result of the jq query of the modifier, for the json operators
+	JSONBody Target = "json_body"
 )
 
 const (
-	Equal Operator = "equal"
-	Regex Operator = "regex"
+	Equal                Operator = "equal"
+	NotEqual             Operator = "not_equal"
+	CaseInsensitiveEqual Operator = "case_insensitive_equal"
+	Regex                Operator = "regex"
+	Contains             Operator = "contains"
+	StartsWith           Operator = "starts_with"
+	EndsWith             Operator = "ends_with"
+	GreaterThan          Operator = "gt"
+	GreaterThanOrEqual   Operator = "gte"
+	LessThan             Operator = "lt"
+	LessThanOrEqual      Operator = "lte"
+	// Exists matches when the target is present in the request, even empty
+	Exists Operator = "exists"
+	Absent Operator = "absent"
+	// In matches when the target is equal to one of the values
+	In Operator = "in"
+	// CIDR matches when the target is an IP address in the network of the value, like 10.0.0.0/8
+	CIDR Operator = "cidr"
+	// JSONEquals matches when the target is the JSON document of the value, whatever the order of the keys and the spaces.
+	// The strings "${any}" and "${regex:pattern}" of the value match any value, and the strings entirely matching the pattern
+	JSONEquals Operator = "json_equals"
+	// JSONContains matches when the target contains the JSON document of the value, the items of the arrays in any order
+	JSONContains Operator = "json_contains"
+)
+
+// The multi value modes of the targets which can have several values, like a repeated query param.
+// The first value is matched if the mode is not set, an index like 1 matches the value at this index
+const (
+	// AnyValue matches when one of the values matches
+	AnyValue = "any"
+	// AllValues matches when each value matches
+	AllValues = "all"
+	// ValueCount matches the number of values, with the numeric operators
+	ValueCount = "count"
+)
+
+var (
+	targets = []interface{}{
+		Header, Body, QueryString, Cookie, RouteParam, RequestNumber, Expression,
+		Method, Path, Host, Scheme, RemoteIP, URL, BasicAuthUser, JWTClaim,
+		FormField, MultipartField, MultipartFileName, MultipartFileContentType, MultipartFileSize, XMLBody, SOAPAction, JSONBody,
+	}
+	operators = []interface{}{
+		Equal, NotEqual, CaseInsensitiveEqual, Regex, Contains, StartsWith, EndsWith,
+		GreaterThan, GreaterThanOrEqual, LessThan, LessThanOrEqual, Exists, Absent, In, CIDR,
+		JSONEquals, JSONContains,
+	}
 )
 
 type Rule struct {
@@ -27,13 +98,95 @@ type Rule struct {
 	Modifier string   `yaml:"modifier" json:"modifier,omitempty"`
 	Value    string   `yaml:"value" json:"value"`
 	Operator Operator `yaml:"operator" json:"operator"`
+	// Values are the values of the in operator
+	Values []string `yaml:"values,omitempty" json:"values,omitempty"`
+	// Negate inverts the result of the operator
+	Negate bool `yaml:"negate,omitempty" json:"negate,omitempty"`
+	// MultiValue is how the values of a header, query param or cookie sent several times are matched
+	MultiValue string `yaml:"multi_value,omitempty" json:"multi_value,omitempty"`
+	// IgnoreNameCase matches the query param or the cookie of the modifier whatever the case of its name
+	IgnoreNameCase bool `yaml:"ignore_name_case,omitempty" json:"ignore_name_case,omitempty"`
+
+	// compiled is the expression compiled when the mock is normalized
+	compiled *compiledExpression
 }
 
 func (r Rule) Validate() error {
 	return validation.ValidateStruct(
 		&r,
-		validation.Field(&r.Target, validation.Required, validation.In(Body, QueryString, Header, Cookie, RouteParam, RequestNumber)),
-		validation.Field(&r.Value, validation.Required),
-		validation.Field(&r.Operator, validation.Required, validation.In(Equal, Regex)),
+		validation.Field(&r.Target, validation.Required, validation.In(targets...)),
+		validation.Field(&r.Modifier,
+			validation.When((r.Target == Body || r.Target == JSONBody) && r.Modifier != "", validation.By(isJQ)),
+			validation.When(r.Target.HasName(), validation.Required),
+			validation.When(r.Target == XMLBody, validation.By(isXPath)),
+		),
+		validation.Field(&r.Value,
+			validation.When(r.Operator.HasValue(), validation.Required),
+			validation.When(!r.Operator.HasValue(), validation.Empty),
+			validation.When(r.Operator == Regex, validation.By(isRegex)),
+			validation.When(r.Operator.IsNumeric(), validation.By(isNumber)),
+			validation.When(r.Operator == CIDR, validation.By(isCIDR)),
+			validation.When(r.Operator == JSONEquals || r.Operator == JSONContains, validation.By(isJSONDocument)),
+			validation.When(r.Target == Expression, validation.By(r.isExpression)),
+		),
+		validation.Field(&r.Operator,
+			validation.When(r.Target != Expression, validation.Required, validation.In(operators...)),
+			validation.When(r.Target == Expression, validation.Empty),
+		),
+		validation.Field(&r.Values,
+			validation.When(r.Operator == In, validation.Required),
+			validation.When(r.Operator != In, validation.Empty),
+		),
+		validation.Field(&r.MultiValue,
+			validation.When(!r.Target.HasMultipleValues(), validation.Empty),
+			validation.By(isMultiValue),
+		),
+		validation.Field(&r.IgnoreNameCase, validation.When(!r.Target.HasMultipleValues(), validation.Empty)),
 	)
 }
+
+// HasName returns true if the modifier is required, as the name of the value in the request
+func (t Target) HasName() bool {
+	switch t {
+	case JWTClaim, FormField, MultipartField, MultipartFileName, MultipartFileContentType, MultipartFileSize, XMLBody:
+		return true
+	}
+	return false
+}
+
+// HasMultipleValues returns true if the target can be sent several times, like a header
+func (t Target) HasMultipleValues() bool {
+	switch t {
+	case Header, QueryString, Cookie:
+		return true
+	}
+	return false
+}
+
+// HasValue returns true if the operator compares the target to the value
+func (o Operator) HasValue() bool {
+	switch o {
+	case Exists, Absent, In:
+		return false
+	}
+	return true
+}
+
+// IsKnown returns true if the operator is one of the operators of the rules
+func (o Operator) IsKnown() bool {
+	for _, operator := range operators {
+		if o == operator {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNumeric returns true if the operator compares the target and the value as numbers
+func (o Operator) IsNumeric() bool {
+	switch o {
+	case GreaterThan, GreaterThanOrEqual, LessThan, LessThanOrEqual:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package mock
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// RuleGroup combines rules and nested groups, so that (A and B) or C can be expressed
+type RuleGroup struct {
+	// RuleAggregation defaults to and, not matches when none of the rules and groups match
+	RuleAggregation RuleAggregation `yaml:"rule_aggregation,omitempty" json:"rule_aggregation,omitempty"`
+	Rules           []Rule          `yaml:"rules,omitempty" json:"rules,omitempty"`
+	RuleGroups      []RuleGroup     `yaml:"rule_groups,omitempty" json:"rule_groups,omitempty"`
+}
+
+func (g RuleGroup) Validate() error {
+	return validation.ValidateStruct(
+		&g,
+		validation.Field(&g.RuleAggregation, validation.In(ruleAggregations...)),
+		validation.Field(&g.Rules, validation.When(len(g.RuleGroups) == 0, validation.Required)),
+		validation.Field(&g.RuleGroups),
+	)
+}
+
+// IsEmpty returns true if the group has no rules nor groups, an empty group always matches
+func (g RuleGroup) IsEmpty() bool {
+	return len(g.Rules) == 0 && len(g.RuleGroups) == 0
+}
+
+// walkRules calls fn with the rules of the group and of its nested groups, and with the keys of their path
+func (g RuleGroup) walkRules(fn func(rule *Rule, keys []interface{}), keys ...interface{}) {
+	for i := range g.Rules {
+		fn(&g.Rules[i], appendKeys(keys, "rules", i))
+	}
+	for i, group := range g.RuleGroups {
+		group.walkRules(fn, appendKeys(keys, "rule_groups", i)...)
+	}
+}
+
+// appendKeys returns a copy of the keys with the new keys, so that the paths of the siblings do not share memory
+func appendKeys(keys []interface{}, more ...interface{}) []interface{} {
+	return append(append(make([]interface{}, 0, len(keys)+len(more)), keys...), more...)
+}
@@ -16,6 +16,40 @@ func TestRule_Validate(t *testing.T) {
 		{"invalid route, missing target", Rule{Target: "", Modifier: "Authorization", Value: "Bearer...", Operator: "equal"}, true},
 		{"invalid route, missing value", Rule{Target: "cookie", Modifier: "Authorization", Value: "", Operator: "equal"}, true},
 		{"invalid route, missing operator", Rule{Target: "body", Modifier: "Authorization", Value: "Bearer...", Operator: ""}, true},
+		{"valid regex", Rule{Target: "header", Modifier: "Authorization", Value: "^Bearer .+$", Operator: "regex"}, false},
+		{"invalid regex", Rule{Target: "header", Modifier: "Authorization", Value: "Bearer (", Operator: "regex"}, true},
+		{"valid jq modifier", Rule{Target: "body", Modifier: ".user.name", Value: "John", Operator: "equal"}, false},
+		{"invalid jq modifier", Rule{Target: "body", Modifier: ".user.", Value: "John", Operator: "equal"}, true},
+		{"unknown operator", Rule{Target: "header", Modifier: "Authorization", Value: "Bearer...", Operator: "like"}, true},
+		{"valid numeric operator", Rule{Target: "request_number", Value: "2.5", Operator: "gte"}, false},
+		{"invalid numeric value", Rule{Target: "request_number", Value: "two", Operator: "lt"}, true},
+		{"valid exists", Rule{Target: "header", Modifier: "Authorization", Operator: "exists", Negate: true}, false},
+		{"invalid absent, with value", Rule{Target: "header", Modifier: "Authorization", Value: "Bearer...", Operator: "absent"}, true},
+		{"valid in", Rule{Target: "query_string", Modifier: "name", Values: []string{"jane", "joe"}, Operator: "in"}, false},
+		{"invalid in, missing values", Rule{Target: "query_string", Modifier: "name", Operator: "in"}, true},
+		{"valid expression", Rule{Target: "expression", Value: `body.items.size() > 3 && headers["X-Tier"] == "gold"`}, false},
+		{"invalid expression, syntax", Rule{Target: "expression", Value: `body.items.size() >`}, true},
+		{"invalid expression, unknown variable", Rule{Target: "expression", Value: `user.name == "joe"`}, true},
+		{"invalid expression, not a bool", Rule{Target: "expression", Value: `request_number + 1`}, true},
+		{"invalid expression, with operator", Rule{Target: "expression", Value: `method == "GET"`, Operator: "equal"}, true},
+		{"valid cidr", Rule{Target: "remote_ip", Value: "10.0.0.0/8", Operator: "cidr"}, false},
+		{"invalid cidr", Rule{Target: "remote_ip", Value: "10.0.0.1", Operator: "cidr"}, true},
+		{"valid jwt claim", Rule{Target: "jwt_claim", Modifier: "org.id", Value: "42", Operator: "equal"}, false},
+		{"invalid jwt claim, missing modifier", Rule{Target: "jwt_claim", Value: "42", Operator: "equal"}, true},
+		{"valid form field", Rule{Target: "form_field", Modifier: "user", Value: "joe", Operator: "equal"}, false},
+		{"invalid multipart file size, missing modifier", Rule{Target: "multipart_file_size", Value: "1024", Operator: "lt"}, true},
+		{"valid xpath", Rule{Target: "xml_body", Modifier: "//stock:GetPrice/stock:Item", Value: "Apple", Operator: "equal"}, false},
+		{"invalid xpath", Rule{Target: "xml_body", Modifier: "//stock:GetPrice[", Value: "Apple", Operator: "equal"}, true},
+		{"valid soap action", Rule{Target: "soap_action", Value: "GetPrice", Operator: "ends_with"}, false},
+		{"valid multi value", Rule{Target: "query_string", Modifier: "tag", Value: "a", Operator: "equal", MultiValue: "all"}, false},
+		{"valid multi value, index", Rule{Target: "header", Modifier: "X-Tag", Value: "a", Operator: "equal", MultiValue: "1"}, false},
+		{"invalid multi value", Rule{Target: "query_string", Modifier: "tag", Value: "a", Operator: "equal", MultiValue: "last"}, true},
+		{"invalid multi value, target", Rule{Target: "body", Value: "a", Operator: "equal", MultiValue: "any"}, true},
+		{"invalid ignore name case, target", Rule{Target: "route_param", Modifier: "id", Value: "a", Operator: "equal", IgnoreNameCase: true}, true},
+		{"valid json", Rule{Target: "json_body", Modifier: ".items", Value: `[{"sku": "${regex:A-[0-9]+}"}]`, Operator: "json_contains"}, false},
+		{"invalid json", Rule{Target: "json_body", Value: `{"sku": }`, Operator: "json_equals"}, true},
+		{"invalid json, regex placeholder", Rule{Target: "json_body", Value: `{"sku": "${regex:A-[0-9}"}`, Operator: "json_equals"}, true},
+		{"invalid values, not in", Rule{Target: "query_string", Modifier: "name", Value: "joe", Values: []string{"jane"}, Operator: "equal"}, true},
 	}
 
 	for _, tt := range tests {
@@ -25,3 +59,31 @@ func TestRule_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestRule_Program(t *testing.T) {
+	mok := &Mock{Routes: []*Route{{Path: "/", Responses: []Response{{
+		Rules:      []Rule{{Target: Expression, Value: `method == "GET"`}},
+		RuleGroups: []RuleGroup{{Rules: []Rule{{Target: Expression, Value: `path == "/"`}}}},
+	}}}}}
+	mok.Normalize()
+	res := mok.Routes[0].Responses[0]
+
+	first, err := res.Rules[0].Program()
+	assert.NoError(t, err)
+	second, err := res.Rules[0].Program()
+	assert.NoError(t, err)
+	assert.Same(t, first, second)
+	assert.NotNil(t, res.RuleGroups[0].Rules[0].compiled)
+
+	rule := res.Rules[0]
+	rule.Value = `method == "POST"`
+	program, err := rule.Program()
+	assert.NoError(t, err)
+	out, _, err := program.Eval(map[string]interface{}{"method": "POST"})
+	assert.NoError(t, err)
+	assert.Equal(t, true, out.Value())
+
+	rule.Value = `method ==`
+	_, err = rule.Program()
+	assert.Error(t, err)
+}
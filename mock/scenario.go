@@ -0,0 +1,27 @@
+package mock
+
+import (
+	"sort"
+)
+
+// StartedState is the state of a scenario until a response moves it to a new state, or after it is reset
+const StartedState = "Started"
+
+// Scenarios returns the sorted names of the scenarios of the responses
+func (m Mock) Scenarios() []string {
+	names := map[string]bool{}
+	for _, route := range m.Routes {
+		for _, response := range route.Responses {
+			if response.Scenario != "" {
+				names[response.Scenario] = true
+			}
+		}
+	}
+
+	scenarios := make([]string, 0, len(names))
+	for name := range names {
+		scenarios = append(scenarios, name)
+	}
+	sort.Strings(scenarios)
+	return scenarios
+}
@@ -0,0 +1,296 @@
+package mock
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const schemaVersion = "http://json-schema.org/draft-07/schema#"
+
+// schema is a JSON Schema, only the keywords used by the mock schema are supported
+type schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 interface{}        `json:"type,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Minimum              *int               `json:"minimum,omitempty"`
+	Maximum              *int               `json:"maximum,omitempty"`
+	MinProperties        int                `json:"minProperties,omitempty"`
+	MaxProperties        int                `json:"maxProperties,omitempty"`
+	Items                *schema            `json:"items,omitempty"`
+	Properties           map[string]*schema `json:"properties,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AnyOf                []*schema          `json:"anyOf,omitempty"`
+	Definitions          map[string]*schema `json:"definitions,omitempty"`
+}
+
+// schemaEnums are the values of the enum types
+var schemaEnums = map[reflect.Type][]interface{}{
+	reflect.TypeOf(Target("")):          targets,
+	reflect.TypeOf(Operator("")):        operators,
+	reflect.TypeOf(RuleAggregation("")): ruleAggregations,
+	reflect.TypeOf(responseMode("")):    responseModes,
+	reflect.TypeOf(pathMode("")):        pathModes,
+	reflect.TypeOf(trailingSlash("")):   trailingSlashes,
+}
+
+// schemaRequired are the required fields of the types
+var schemaRequired = map[string][]string{
+	"Mock":  {"routes"},
+	"Route": {"path", "responses"},
+	"Rule":  {"target"},
+}
+
+// schemaDescriptions are the descriptions of the types and of their fields, by type or type.field
+var schemaDescriptions = map[string]string{
+	"Mock":                         "A mock server, with the routes it responds to",
+	"Mock.version":                 "The version of the mock format, the older documents are migrated when they are loaded",
+	"Mock.id":                      "The ID of the mock, it is generated if it is not set",
+	"Mock.name":                    "The name of the mock",
+	"Mock.port":                    "The port the mock server listens to, a random port is used if it is not set",
+	"Mock.routes":                  "The routes of the mock, the first route matching the request responds",
+	"Mock.proxy":                   "The server the requests which match no route are forwarded to",
+	"Mock.auto_cors":               "Deprecated: use cors. All OPTIONS calls are responded with success if it is true",
+	"Mock.cors":                    "The CORS policy answering the preflight requests, and decorating the cross origin responses",
+	"Mock.namespaces":              "The XML namespaces of the XPath of the xml_body rules by their prefix, the prefixes of the request body do not matter",
+	"Mock.fallback":                "The response written to the requests which match no route, an empty 404 is written if it is not set",
+	"Mock.base_path":               "The path prefixing the paths of all the routes, like /v1",
+	"Mock.path_options":            "How the paths of the requests are normalized before they are matched to the route paths",
+	"PathOptions":                  "How the paths of the requests are normalized before they are matched to the route paths",
+	"PathOptions.trailing_slash":   "How a path differing from a route path by a trailing slash is handled, it does not match if it is not set, ignore matches it and redirect redirects it to the route path",
+	"PathOptions.case_insensitive": "The literal parts of the paths are matched whatever their case if it is true, the params keep their case",
+	"PathOptions.merge_slashes":    "The duplicate slashes of the paths, like //users, are merged if it is true",
+	"PathOptions.decode_path":      "The whole path is decoded before it is matched if it is true, so that the encoded slashes separate the segments",
+	"Route":                        "A route, matched by the method and the path of the request",
+	"Route.id":                     "The ID of the route, it is generated if it is not set",
+	"Route.method":                 "The HTTP method of the route, ANY matches all the methods. GET if it is not set, the GET routes also answer the HEAD requests",
+	"Route.methods":                "The HTTP methods of a route matching several methods, instead of method",
+	"Route.path":                   "The path of the route. :name declares a param matching a segment, :name(regex) a param matching the regex, *name a param matching the rest of the path, and * matches any part of a segment. A segment followed by ? is optional. A regex matching the whole path in the regex path mode",
+	"Route.path_mode":              "How the path is matched, with the params if it is not set, or as a regex whose named groups are the route params",
+	"Route.query":                  "The query params the request must have to match the route, a param whose value is * can have any value",
+	"Route.description":            "The description of the route",
+	"Route.response_mode":          "How the response is chosen, by the rules if it is not set, randomly or sequentially",
+	"Route.responses":              "The responses of the route, the first response whose rules match is written",
+	"Response":                     "A response of a route, written when its rules match the request",
+	"Response.id":                  "The ID of the response, it is generated if it is not set",
+	"Response.status":              "The HTTP status of the response, 200 if it is not set",
+	"Response.delay":               "The delay before the response is written, in milliseconds",
+	"Response.headers":             "The headers of the response, a header is written once for each of its values",
+	"Response.body":                "The body of the response",
+	"Response.template":            "The body is rendered as a text/template with the request if it is true, the json and xml functions escape the values",
+	"Response.rule_aggregation":    "How the rules and the rule groups are combined, and if it is not set",
+	"Response.rule_groups":         "The nested groups of rules, combined with the rules",
+	"Response.rules":               "The rules the request must match for the response to be written",
+	"Response.is_default":          "The default response is written when the rules of no other response match",
+	"Response.scenario":            "The name of the scenario the required and the new states belong to, its state is kept by session",
+	"Response.required_state":      "The state the scenario must be in for the response to match, Started until it moves",
+	"Response.new_state":           "The state the scenario moves to when the response is written",
+	"Rule":                         "A rule matching a value of the request",
+	"Rule.id":                      "The ID of the rule, it is generated if it is not set",
+	"Rule.target":                  "The part of the request the rule matches, expression evaluates the CEL expression of the value against the whole request",
+	"Rule.modifier":                "The name of the header, query string, cookie, route param or form field, a jq query on the body, an XPath on the XML body, or the path of the jwt claim like org.id",
+	"Rule.value":                   "The value the target is compared to, a JSON document where ${any} and ${regex:pattern} are placeholders for the json operators. It is not set for the exists, absent and in operators. The CEL expression of the expression target",
+	"Rule.operator":                "How the target is compared to the value, gt, gte, lt and lte compare numbers, cidr matches the IP addresses of a network, json_equals and json_contains compare JSON documents. It is not set for the expression target",
+	"Rule.values":                  "The values the target is compared to by the in operator",
+	"Rule.negate":                  "The rule matches when the operator does not match if it is true",
+	"Rule.multi_value":             "How the values of a header, query param or cookie sent several times are matched, any, all, count or the index of a value. The first value is matched if it is not set",
+	"Rule.ignore_name_case":        "The query param or the cookie of the modifier is matched whatever the case of its name if it is true",
+	"RuleGroup":                    "A group of rules and nested groups, so that (A and B) or C can be expressed",
+	"RuleGroup.rule_aggregation":   "How the rules and the groups are combined, and if it is not set, not matches when none of them match",
+	"RuleGroup.rules":              "The rules of the group",
+	"RuleGroup.rule_groups":        "The nested groups",
+	"Proxy":                        "The server the requests which match no route are forwarded to",
+	"Proxy.enabled":                "The requests are forwarded if it is true",
+	"Proxy.host":                   "The URL of the server the requests are forwarded to",
+	"Proxy.request_headers":        "The headers added to the forwarded requests",
+	"Proxy.response_headers":       "The headers added to the responses of the server",
+	"CORS":                         "The CORS policy of the mock",
+	"CORS.enabled":                 "The policy is applied if it is true",
+	"CORS.allow_origins":           "The allowed origins, all the origins are allowed if it is empty or contains *",
+	"CORS.allow_methods":           "The allowed methods, GET, HEAD, PUT, PATCH, POST and DELETE if it is not set",
+	"CORS.allow_headers":           "The allowed headers, the headers requested by the preflight request if it is not set",
+	"CORS.expose_headers":          "The headers the browsers expose to the scripts",
+	"CORS.allow_credentials":       "The cross origin requests can include credentials if it is true",
+	"CORS.preflight_only":          "Only the preflight requests are answered, the other responses are not decorated, like auto_cors did",
+	"CORS.max_age":                 "How long, in seconds, the preflight response can be cached",
+	"Fallback":                     "The response written when a request matches none of the routes",
+	"Fallback.status":              "The HTTP status of the response, 404 if it is not set",
+	"Fallback.headers":             "The headers of the response, a header is written once for each of its values",
+	"Fallback.body":                "The body of the response",
+	"Fallback.template":            "The body is rendered as a text/template with the request and the closest routes if it is true, the json and xml functions escape the values",
+	"Fallback.strict":              "The response is a 501 explaining why the request was not matched if it is true",
+}
+
+// JSONSchema returns the JSON Schema of the mock files, editors use it to autocomplete and validate the mocks
+func JSONSchema() ([]byte, error) {
+	b := &schemaBuilder{described: map[string]bool{}, definitions: map[string]*schema{
+		"include": {
+			Description: "Includes a file, relative to the including file. In a list, the files matching a glob pattern are included",
+			Type:        "object",
+			Properties: map[string]*schema{
+				"$ref":    {Type: "string", Description: "The file, or the glob pattern, which is included"},
+				"include": {Type: "string", Description: "The file, or the glob pattern, which is included"},
+			},
+			AdditionalProperties: false,
+			MinProperties:        1,
+			MaxProperties:        1,
+		},
+	}}
+
+	root := b.definition(reflect.TypeOf(Mock{}))
+	root.Schema = schemaVersion
+	root.Title = "Mock"
+	root.Definitions = b.definitions
+	delete(b.definitions, "Mock")
+
+	if err := b.checkDescriptions(); err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(data, '\n'), nil
+}
+
+type schemaBuilder struct {
+	definitions map[string]*schema
+	// described are the types and the fields of the schema, by type or type.field
+	described map[string]bool
+}
+
+// description returns the description of the type or of the field, by type or type.field
+func (b *schemaBuilder) description(key string) string {
+	b.described[key] = true
+	return schemaDescriptions[key]
+}
+
+// checkDescriptions returns an error if a type or a field of the schema has no description,
+// or if a description belongs to no type or field, so that the descriptions follow the structs
+func (b *schemaBuilder) checkDescriptions() error {
+	var missing, unused []string
+	for key := range b.described {
+		if schemaDescriptions[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+	for key := range schemaDescriptions {
+		if !b.described[key] {
+			unused = append(unused, key)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(unused)
+
+	switch {
+	case len(missing) > 0:
+		return errors.Errorf("the schema descriptions of %s are missing", strings.Join(missing, ", "))
+	case len(unused) > 0:
+		return errors.Errorf("the schema descriptions of %s belong to no field", strings.Join(unused, ", "))
+	}
+	return nil
+}
+
+// definition returns the schema of the struct, the structs it references are added to the definitions
+func (b *schemaBuilder) definition(t reflect.Type) *schema {
+	s := &schema{
+		Description:          b.description(t.Name()),
+		Type:                 "object",
+		Properties:           map[string]*schema{},
+		AdditionalProperties: false,
+		Required:             schemaRequired[t.Name()],
+	}
+	b.definitions[t.Name()] = s
+
+	for name, field := range yamlFields(t) {
+		property := b.schema(field.Type)
+		if property.Ref != "" || name == "body" {
+			// the objects and the bodies can be included from another file
+			property = &schema{AnyOf: []*schema{property, {Ref: "#/definitions/include"}}}
+		}
+		property.Description = b.description(t.Name() + "." + name)
+		if numericStrings[t.Name()+"."+name] {
+			property.Type = []string{"string", "integer"}
+		}
+
+		switch t.Name() + "." + name {
+		case "Mock.version":
+			property.Minimum, property.Maximum = intPtr(1), intPtr(CurrentVersion)
+		case "Route.method":
+			property.Enum = append(methodEnum(), AnyMethod, strings.ToLower(AnyMethod))
+		case "Route.methods":
+			property.Items.Enum = append(methodEnum(), AnyMethod, strings.ToLower(AnyMethod))
+		case "CORS.allow_methods":
+			property.Items.Enum = methodEnum()
+		}
+
+		s.Properties[name] = property
+	}
+
+	return s
+}
+
+func (b *schemaBuilder) schema(t reflect.Type) *schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if values, ok := schemaEnums[t]; ok {
+		return &schema{Type: "string", Enum: values}
+	}
+
+	if t == headersType {
+		values := &schema{AnyOf: []*schema{{Type: "string"}, {Type: "array", Items: &schema{Type: "string"}}}}
+		return &schema{Type: "object", AdditionalProperties: values}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if _, ok := b.definitions[t.Name()]; !ok {
+			b.definition(t)
+		}
+		return &schema{Ref: "#/definitions/" + t.Name()}
+	case reflect.Map:
+		return &schema{Type: "object", AdditionalProperties: b.schema(t.Elem())}
+	case reflect.Slice:
+		items := b.schema(t.Elem())
+		if items.Ref != "" {
+			items = &schema{AnyOf: []*schema{items, {Ref: "#/definitions/include"}}}
+		}
+		return &schema{Type: "array", Items: items}
+	case reflect.String:
+		return &schema{Type: "string"}
+	case reflect.Bool:
+		return &schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &schema{Type: "integer"}
+	}
+
+	return &schema{}
+}
+
+// methodEnum returns the HTTP methods, the methods are not case sensitive
+func methodEnum() []interface{} {
+	var values []interface{}
+	for _, method := range httpMethods {
+		values = append(values, method)
+	}
+	for _, method := range httpMethods {
+		values = append(values, strings.ToLower(method))
+	}
+	return values
+}
+
+func intPtr(v int) *int {
+	return &v
+}
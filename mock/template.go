@@ -0,0 +1,44 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"text/template"
+)
+
+// templateFuncs are the functions of the templated bodies, of the responses and of the fallback
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	// xml escapes the value for XML text and attributes, like the values of the SOAP envelopes
+	"xml": func(v interface{}) (string, error) {
+		var buf bytes.Buffer
+		err := xml.EscapeText(&buf, []byte(fmt.Sprint(v)))
+		return buf.String(), err
+	},
+}
+
+// renderTemplate executes the body as a text/template against data
+func renderTemplate(body string, data interface{}) ([]byte, error) {
+	tpl, err := template.New("body").Funcs(templateFuncs).Parse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func isTemplate(value interface{}) error {
+	text, _ := value.(string)
+	_, err := template.New("body").Funcs(templateFuncs).Parse(text)
+	return err
+}
@@ -0,0 +1,184 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/antchfx/xpath"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/itchyny/gojq"
+	"github.com/pkg/errors"
+)
+
+var httpMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+	http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace,
+}
+
+func isRegex(value interface{}) error {
+	text, _ := value.(string)
+	if _, err := regexp.Compile(text); err != nil {
+		return fmt.Errorf("must be a valid regular expression: %v", err)
+	}
+	return nil
+}
+
+func isNumber(value interface{}) error {
+	text, _ := value.(string)
+	if _, err := strconv.ParseFloat(strings.TrimSpace(text), 64); err != nil {
+		return errors.New("must be a number")
+	}
+	return nil
+}
+
+func isCIDR(value interface{}) error {
+	text, _ := value.(string)
+	if _, _, err := net.ParseCIDR(strings.TrimSpace(text)); err != nil {
+		return errors.New("must be a valid CIDR notation, like 10.0.0.0/8")
+	}
+	return nil
+}
+
+func isXPath(value interface{}) error {
+	text, _ := value.(string)
+	if _, err := xpath.Compile(text); err != nil {
+		return fmt.Errorf("must be a valid XPath: %v", err)
+	}
+	return nil
+}
+
+// isJSONDocument checks the value of the json operators, and the patterns of its ${regex:pattern} placeholders
+func isJSONDocument(value interface{}) error {
+	text, _ := value.(string)
+	var document interface{}
+	if err := json.Unmarshal([]byte(text), &document); err != nil {
+		return fmt.Errorf("must be a valid JSON document: %v", err)
+	}
+	return checkPlaceholders(document)
+}
+
+func checkPlaceholders(value interface{}) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, item := range v {
+			if err := checkPlaceholders(item); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if err := checkPlaceholders(item); err != nil {
+				return err
+			}
+		}
+	case string:
+		if strings.HasPrefix(v, "${regex:") && strings.HasSuffix(v, "}") {
+			if _, err := regexp.Compile(v[len("${regex:") : len(v)-1]); err != nil {
+				return fmt.Errorf("must have valid placeholders: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// isMultiValue checks the multi value mode, any, all, count or the index of a value
+func isMultiValue(value interface{}) error {
+	mode, _ := value.(string)
+	switch mode {
+	case "", AnyValue, AllValues, ValueCount:
+		return nil
+	}
+
+	if index, err := strconv.Atoi(mode); err != nil || index < 0 {
+		return errors.New("must be any, all, count or the index of a value")
+	}
+	return nil
+}
+
+// isJQ checks that the jq query compiles, so that the undefined functions, like .first-name, are reported too
+func isJQ(value interface{}) error {
+	text, _ := value.(string)
+	query, err := gojq.Parse(text)
+	if err == nil {
+		_, err = gojq.Compile(query)
+	}
+	if err != nil {
+		return fmt.Errorf("must be a valid jq query: %v", err)
+	}
+	return nil
+}
+
+// isRouteMethod checks the method of a route, ANY or an HTTP method
+func isRouteMethod(value interface{}) error {
+	if text, _ := value.(string); strings.EqualFold(text, AnyMethod) {
+		return nil
+	}
+	if err := isHTTPMethod(value); err != nil {
+		return fmt.Errorf("must be %s or one of %s", AnyMethod, strings.Join(httpMethods, ", "))
+	}
+	return nil
+}
+
+func isHTTPMethod(value interface{}) error {
+	text, _ := value.(string)
+	for _, method := range httpMethods {
+		if strings.EqualFold(text, method) {
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of %s", strings.Join(httpMethods, ", "))
+}
+
+// toValidationErrors returns the errors of ValidateStruct as a map the other errors can be added to
+func toValidationErrors(err error) (validation.Errors, error) {
+	if err == nil {
+		return validation.Errors{}, nil
+	}
+
+	errs, ok := err.(validation.Errors)
+	if !ok {
+		return nil, err
+	}
+
+	return errs, nil
+}
+
+// addError adds the error to the nested validation errors at the keys, unless the field or one of its parents already failed
+func addError(errs validation.Errors, err error, keys ...interface{}) {
+	for i, key := range keys {
+		name := fmt.Sprint(key)
+		existing, found := errs[name]
+		if i == len(keys)-1 {
+			if !found {
+				errs[name] = err
+			}
+			return
+		}
+
+		if !found {
+			nested := validation.Errors{}
+			errs[name] = nested
+			errs = nested
+			continue
+		}
+
+		nested, ok := existing.(validation.Errors)
+		if !ok {
+			return
+		}
+		errs = nested
+	}
+}
+
+func toKeys(keys []interface{}) []string {
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = fmt.Sprint(key)
+	}
+	return names
+}
@@ -0,0 +1,177 @@
+package mock_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/mockingio/engine/mock"
+)
+
+func TestMock_Validate(t *testing.T) {
+	t.Run("all the problems are reported at once", func(t *testing.T) {
+		_, err := FromYaml(`id: mock
+routes:
+  - id: users
+    method: FETCH
+    path: /users/:id
+    responses:
+      - id: ok
+        status: 200
+        is_default: true
+        rules:
+          - target: route_param
+            modifier: user_id
+            value: "1"
+            operator: equal
+          - target: body
+            modifier: .name[
+            value: "(john"
+            operator: regex
+      - id: ok
+        is_default: true
+  - id: mock
+    path: /health
+    responses:
+      - status: 200
+`, WithValidation())
+
+		assert.Equal(t, Errors{
+			{Line: 4, Column: 13, Path: "routes[0].method", Message: "must be ANY or one of GET, HEAD, POST, PUT, PATCH, DELETE, CONNECT, OPTIONS, TRACE"},
+			{Line: 12, Column: 23, Path: "routes[0].responses[0].rules[0].modifier", Message: "must be a param of the path /users/:id"},
+			{Line: 16, Column: 23, Path: "routes[0].responses[0].rules[1].modifier", Message: "must be a valid jq query: unexpected EOF"},
+			{Line: 17, Column: 20, Path: "routes[0].responses[0].rules[1].value", Message: "must be a valid regular expression: error parsing regexp: missing closing ): `(john`"},
+			{Line: 19, Column: 13, Path: "routes[0].responses[1].id", Message: "must be unique, ok is already used by routes[0].responses[0].id"},
+			{Line: 20, Column: 21, Path: "routes[0].responses[1].is_default", Message: "must be set on one response only, responses[0] is already the default"},
+			{Line: 21, Column: 9, Path: "routes[1].id", Message: "must be unique, mock is already used by id"},
+		}, err)
+	})
+
+	t.Run("lower case methods and declared route params are valid", func(t *testing.T) {
+		mock, err := FromYaml(`routes:
+  - method: post
+    path: /users/:id/orders/:order_id
+    responses:
+      - status: 200
+        rules:
+          - target: route_param
+            modifier: order_id
+            value: "1"
+            operator: equal
+          - target: body
+            modifier: .items[0].sku
+            value: "^SKU-[0-9]+$"
+            operator: regex
+`, WithValidation())
+		require.NoError(t, err)
+		assert.Len(t, mock.Routes, 1)
+	})
+
+	t.Run("routes with several methods are valid", func(t *testing.T) {
+		mock, err := FromYaml(`routes:
+  - methods: [put, PATCH]
+    path: /users/:id
+    responses:
+      - status: 204
+`, WithValidation())
+		require.NoError(t, err)
+		assert.Empty(t, mock.Routes[0].Method)
+		assert.Equal(t, []string{"put", "PATCH"}, mock.Routes[0].Methods)
+	})
+
+	t.Run("the path options are validated", func(t *testing.T) {
+		_, err := FromYaml(`base_path: v1
+path_options:
+  trailing_slash: append
+routes:
+  - path: /users
+    responses:
+      - status: 200
+`, WithValidation())
+
+		assert.Equal(t, Errors{
+			{Line: 1, Column: 12, Path: "base_path", Message: "must start with a slash, like /v1"},
+			{Line: 3, Column: 19, Path: "path_options.trailing_slash", Message: "must be a valid value"},
+		}, err)
+	})
+
+	t.Run("the states of the responses belong to a scenario", func(t *testing.T) {
+		_, err := FromYaml(`routes:
+  - path: /orders/:id
+    responses:
+      - status: 200
+        required_state: shipped
+      - status: 200
+        scenario: order
+        new_state: delivered
+`, WithValidation())
+
+		assert.Equal(t, Errors{
+			{Line: 4, Column: 9, Path: "routes[0].responses[0].scenario", Message: "must be set with the required or the new state"},
+		}, err)
+	})
+
+	t.Run("the jq queries must compile", func(t *testing.T) {
+		_, err := FromYaml(`routes:
+  - path: /users
+    responses:
+      - status: 200
+        rules:
+          - target: body
+            modifier: .first-name
+            value: Ann
+            operator: equal
+          - target: body
+            modifier: '."first-name"'
+            value: Ann
+            operator: equal
+`, WithValidation())
+
+		assert.Equal(t, Errors{
+			{Line: 7, Column: 23, Path: "routes[0].responses[0].rules[0].modifier", Message: "must be a valid jq query: function not defined: name/0"},
+		}, err)
+	})
+
+	t.Run("the problems of the nested rule groups are located", func(t *testing.T) {
+		_, err := FromYaml(`routes:
+  - path: /users/:id
+    responses:
+      - status: 200
+        rule_aggregation: or
+        rules:
+          - id: admin
+            target: header
+            modifier: X-Role
+            value: admin
+            operator: equal
+        rule_groups:
+          - rule_aggregation: and
+            rule_groups:
+              - rule_aggregation: not
+                rules:
+                  - id: admin
+                    target: route_param
+                    modifier: user_id
+                    operator: exists
+          - rule_aggregation: xor
+`, WithValidation())
+
+		assert.Equal(t, Errors{
+			{Line: 17, Column: 25, Path: "routes[0].responses[0].rule_groups[0].rule_groups[0].rules[0].id", Message: "must be unique, admin is already used by routes[0].responses[0].rules[0].id"},
+			{Line: 19, Column: 31, Path: "routes[0].responses[0].rule_groups[0].rule_groups[0].rules[0].modifier", Message: "must be a param of the path /users/:id"},
+			{Line: 21, Column: 13, Path: "routes[0].responses[0].rule_groups[1].rules", Message: "cannot be blank"},
+			{Line: 21, Column: 31, Path: "routes[0].responses[0].rule_groups[1].rule_aggregation", Message: "must be a valid value"},
+		}, err)
+	})
+
+	t.Run("errors of the nested validators", func(t *testing.T) {
+		mock := &Mock{
+			Routes: []*Route{
+				{Path: "/hello", Responses: []Response{{Status: 200, Rules: []Rule{{Target: Header, Modifier: "name"}}}}},
+			},
+		}
+
+		assert.EqualError(t, mock.Validate(), "routes: (0: (responses: (0: (rules: (0: (operator: cannot be blank; value: cannot be blank.).).).).).).")
+	})
+}
@@ -0,0 +1,283 @@
+// Package pathpattern matches the request paths against the route paths.
+//
+// A route path is made of segments separated by slashes:
+//   - a literal segment, like users, where * matches any part of the segment, like *.txt
+//   - a param, like :id, which matches a whole segment
+//   - a constrained param, like :id(\d+), whose value must entirely match the regex
+//   - a catch-all, like *rest, which matches the rest of the path, slashes included. It must be the last segment,
+//     and a single * as the last segment is an anonymous catch-all
+//
+// A segment followed by a question mark, like :page?, is optional.
+// The path is matched segment by segment, the values of the params are URL decoded.
+//
+// A regex path matches the whole URL decoded path, its named groups are the params
+package pathpattern
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var catchAllRegex = regexp.MustCompile(`^\*\w*$`)
+
+// Pattern is a compiled route path
+type Pattern struct {
+	segments []segment
+	// regex is the regex of a regex path, the segments are not set. regexFold ignores the case
+	regex     *regexp.Regexp
+	regexFold *regexp.Regexp
+}
+
+type segment struct {
+	// literal is the text of a literal segment without wildcard
+	literal string
+	// glob matches a literal segment with wildcards, globFold ignores the case
+	glob     *regexp.Regexp
+	globFold *regexp.Regexp
+	// name is the name of a param or a catch-all, it is empty for the anonymous catch-all
+	name string
+	// constraint is the regex a param must match
+	constraint *regexp.Regexp
+	isParam    bool
+	catchAll   bool
+	optional   bool
+}
+
+// Compile compiles the route path. The result is not cached, the routes keep their compiled path
+func Compile(path string) (*Pattern, error) {
+	return compile(path)
+}
+
+// CompileRegex compiles the regex of a regex path, it must match the whole path
+func CompileRegex(expr string) (*Pattern, error) {
+	return compileRegex(expr)
+}
+
+func compileRegex(expr string) (*Pattern, error) {
+	regex, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return nil, err
+	}
+	return &Pattern{regex: regex, regexFold: regexp.MustCompile("(?i)" + regex.String())}, nil
+}
+
+func compile(path string) (*Pattern, error) {
+	parts := split(path)
+	pattern := &Pattern{}
+	names := map[string]bool{}
+
+	for i, part := range parts {
+		s, err := parseSegment(part, i == len(parts)-1)
+		if err != nil {
+			return nil, errors.Wrapf(err, "segment %q", part)
+		}
+
+		if s.name != "" {
+			if names[s.name] {
+				return nil, errors.Errorf("param %q is declared twice", s.name)
+			}
+			names[s.name] = true
+		}
+
+		pattern.segments = append(pattern.segments, s)
+	}
+
+	return pattern, nil
+}
+
+func parseSegment(part string, last bool) (segment, error) {
+	s := segment{}
+	if len(part) > 1 && strings.HasSuffix(part, "?") {
+		s.optional = true
+		part = strings.TrimSuffix(part, "?")
+	}
+
+	switch {
+	case strings.HasPrefix(part, ":"):
+		s.isParam = true
+		s.name = part[1:]
+		if i := strings.Index(s.name, "("); i >= 0 {
+			if !strings.HasSuffix(s.name, ")") {
+				return s, errors.New("the constraint of the param is not closed")
+			}
+			constraint, err := regexp.Compile("^(?:" + s.name[i+1:len(s.name)-1] + ")$")
+			if err != nil {
+				return s, errors.Wrap(err, "compile constraint")
+			}
+			s.name, s.constraint = s.name[:i], constraint
+		}
+		if s.name == "" {
+			return s, errors.New("the param has no name")
+		}
+	case catchAllRegex.MatchString(part) && (part != "*" || last):
+		if !last {
+			return s, errors.New("the catch-all must be the last segment")
+		}
+		s.catchAll = true
+		s.name = part[1:]
+	case strings.Contains(part, "*"):
+		quoted := strings.Split(part, "*")
+		for i := range quoted {
+			quoted[i] = regexp.QuoteMeta(quoted[i])
+		}
+		s.glob = regexp.MustCompile("^" + strings.Join(quoted, ".*") + "$")
+		s.globFold = regexp.MustCompile("(?i)" + s.glob.String())
+	default:
+		s.literal = part
+	}
+
+	return s, nil
+}
+
+// Match matches the escaped path of the request, like the EscapedPath of its URL, and returns the values of the params
+func (p *Pattern) Match(path string) (map[string]string, bool) {
+	return p.match(path, false)
+}
+
+// MatchIgnoreCase matches the path like Match, the literal segments and the regex path ignore the case
+func (p *Pattern) MatchIgnoreCase(path string) (map[string]string, bool) {
+	return p.match(path, true)
+}
+
+func (p *Pattern) match(path string, fold bool) (map[string]string, bool) {
+	if p.regex != nil {
+		regex := p.regex
+		if fold {
+			regex = p.regexFold
+		}
+		return matchRegex(regex, unescape(path))
+	}
+
+	params := map[string]string{}
+	if !match(p.segments, strings.Split(path, "/"), params, fold) {
+		return nil, false
+	}
+	return params, true
+}
+
+// matchRegex matches the regex to the decoded path, the named groups which did not participate in the match are not set
+func matchRegex(regex *regexp.Regexp, path string) (map[string]string, bool) {
+	indexes := regex.FindStringSubmatchIndex(path)
+	if indexes == nil {
+		return nil, false
+	}
+
+	params := map[string]string{}
+	for i, name := range regex.SubexpNames() {
+		if name != "" && indexes[2*i] >= 0 {
+			params[name] = path[indexes[2*i]:indexes[2*i+1]]
+		}
+	}
+	return params, true
+}
+
+// Params returns the names of the params and of the named catch-all, or of the named groups of a regex path
+func (p *Pattern) Params() []string {
+	var names []string
+	if p.regex != nil {
+		for _, name := range p.regex.SubexpNames() {
+			if name != "" {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+
+	for _, s := range p.segments {
+		if s.name != "" {
+			names = append(names, s.name)
+		}
+	}
+	return names
+}
+
+// match matches the segments to the parts of the path, the optional segments are matched if possible
+func match(segments []segment, parts []string, params map[string]string, fold bool) bool {
+	if len(segments) == 0 {
+		return len(parts) == 0
+	}
+
+	s := segments[0]
+	if s.catchAll {
+		if len(parts) == 0 {
+			return s.optional
+		}
+		values := make([]string, len(parts))
+		for i, part := range parts {
+			values[i] = unescape(part)
+		}
+		if s.name != "" {
+			params[s.name] = strings.Join(values, "/")
+		}
+		return true
+	}
+
+	if len(parts) > 0 {
+		value := unescape(parts[0])
+		if s.matches(value, fold) {
+			if s.isParam {
+				params[s.name] = value
+			}
+			if match(segments[1:], parts[1:], params, fold) {
+				return true
+			}
+			delete(params, s.name)
+		}
+	}
+
+	return s.optional && match(segments[1:], parts, params, fold)
+}
+
+// matches returns true if the value matches the segment, the case of the literal segments is ignored if fold is true
+func (s segment) matches(value string, fold bool) bool {
+	switch {
+	case s.isParam:
+		return value != "" && (s.constraint == nil || s.constraint.MatchString(value))
+	case s.glob != nil && fold:
+		return s.globFold.MatchString(value)
+	case s.glob != nil:
+		return s.glob.MatchString(value)
+	case fold:
+		return strings.EqualFold(value, s.literal)
+	default:
+		return value == s.literal
+	}
+}
+
+// ParamName returns the name of the param or of the catch-all of a segment of a route path
+func ParamName(part string) (string, bool) {
+	s, err := parseSegment(part, true)
+	if err != nil || s.name == "" {
+		return "", false
+	}
+	return s.name, true
+}
+
+// split splits the route path in segments, the slashes of the constraints do not separate segments
+func split(path string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range path {
+		switch {
+		case c == '(' && strings.HasPrefix(path[start:], ":"):
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		case c == '/' && depth == 0:
+			parts = append(parts, path[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, path[start:])
+}
+
+// unescape decodes a segment of the path, the segments which are not escaped properly are kept as they are
+func unescape(part string) string {
+	if value, err := url.PathUnescape(part); err == nil {
+		return value
+	}
+	return part
+}
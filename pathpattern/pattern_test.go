@@ -0,0 +1,116 @@
+package pathpattern_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mockingio/engine/pathpattern"
+)
+
+func TestPattern_Match(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		matched bool
+		params  map[string]string
+	}{
+		{"/users", "/users", true, map[string]string{}},
+		{"/users", "/users/", false, nil},
+		{"/users/:id", "/users/42", true, map[string]string{"id": "42"}},
+		{"/users/:id", "/users/42/posts", false, nil},
+		{"/users/:id", "/users/", false, nil},
+		{"/users/:id/posts/:post", "/users/42/posts/7", true, map[string]string{"id": "42", "post": "7"}},
+		{`/users/:id(\d+)`, "/users/42", true, map[string]string{"id": "42"}},
+		{`/users/:id(\d+)`, "/users/me", false, nil},
+		{`/files/:path([a-z]+/[a-z]+)`, "/files/a/b", false, nil},
+		{"/users/:id?", "/users", true, map[string]string{}},
+		{"/users/:id?", "/users/42", true, map[string]string{"id": "42"}},
+		{"/users/:id?/posts", "/users/posts", true, map[string]string{}},
+		{"/users/:id?/posts", "/users/42/posts", true, map[string]string{"id": "42"}},
+		{"/api/v1?/users", "/api/users", true, map[string]string{}},
+		{"/api/v1?/users", "/api/v1/users", true, map[string]string{}},
+		{"/files/*rest", "/files/a/b.txt", true, map[string]string{"rest": "a/b.txt"}},
+		{"/files/*rest", "/files", false, nil},
+		{"/files/*rest?", "/files", true, map[string]string{}},
+		{"/*", "/anything/at/all", true, map[string]string{}},
+		{"/orders/*/items", "/orders/1/items", true, map[string]string{}},
+		{"/orders/*/items", "/orders/1/2/items", false, nil},
+		{"/files/*.txt", "/files/notes.txt", true, map[string]string{}},
+		{"/files/*.txt", "/files/notes.md", false, nil},
+		{"/users/:name", "/users/Ann%20Lee", true, map[string]string{"name": "Ann Lee"}},
+		{"/users/:name", "/users/a%2Fb", true, map[string]string{"name": "a/b"}},
+		{"/files/*rest", "/files/my%20docs/a.txt", true, map[string]string{"rest": "my docs/a.txt"}},
+		{"/users/:name", "/users/100%", true, map[string]string{"name": "100%"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			pattern, err := pathpattern.Compile(tt.pattern)
+			require.NoError(t, err)
+
+			params, matched := pattern.Match(tt.path)
+			assert.Equal(t, tt.matched, matched)
+			assert.Equal(t, tt.params, params)
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	for _, path := range []string{
+		"/users/:",
+		`/users/:id(\d+`,
+		`/users/:id([)`,
+		"/files/*rest/edit",
+		"/users/:id/posts/:id",
+	} {
+		t.Run(path, func(t *testing.T) {
+			_, err := pathpattern.Compile(path)
+			assert.Error(t, err)
+		})
+	}
+
+	pattern, err := pathpattern.Compile(`/orgs/:org/users/:id(\d+)?/*rest`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"org", "id", "rest"}, pattern.Params())
+}
+
+func TestCompileRegex(t *testing.T) {
+	pattern, err := pathpattern.CompileRegex(`/files/(?P<name>[a-z ]+)\.(?P<ext>txt|md)(/(?P<version>\d+))?`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name", "ext", "version"}, pattern.Params())
+
+	params, matched := pattern.Match("/files/my%20notes.txt")
+	assert.True(t, matched)
+	assert.Equal(t, map[string]string{"name": "my notes", "ext": "txt"}, params)
+
+	params, matched = pattern.Match("/files/notes.md/2")
+	assert.True(t, matched)
+	assert.Equal(t, map[string]string{"name": "notes", "ext": "md", "version": "2"}, params)
+
+	_, matched = pattern.Match("/files/notes.txt.bak")
+	assert.False(t, matched)
+
+	_, err = pathpattern.CompileRegex(`/files/(?P<name>[a-z`)
+	assert.Error(t, err)
+}
+
+func TestPattern_MatchIgnoreCase(t *testing.T) {
+	pattern, err := pathpattern.Compile("/Users/:id/*.JSON")
+	require.NoError(t, err)
+
+	params, matched := pattern.MatchIgnoreCase("/users/Ann/profile.json")
+	assert.True(t, matched)
+	assert.Equal(t, map[string]string{"id": "Ann"}, params)
+
+	_, matched = pattern.Match("/users/Ann/profile.json")
+	assert.False(t, matched)
+
+	regex, err := pathpattern.CompileRegex(`/Files/(?P<name>[a-z]+)`)
+	require.NoError(t, err)
+
+	params, matched = regex.MatchIgnoreCase("/files/Notes")
+	assert.True(t, matched)
+	assert.Equal(t, map[string]string{"name": "Notes"}, params)
+}
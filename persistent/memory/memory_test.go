@@ -230,6 +230,19 @@ func TestMemory_PatchResponse(t *testing.T) {
 		assert.Equal(t, 201, mok.Routes[1].Responses[1].Status)
 	})
 
+	t.Run("headers with a single value or a list of values", func(t *testing.T) {
+		err := m.PatchResponse(context.Background(), "mockid", "routeid2", "responseid1",
+			`{"headers": {"Content-Type": "text/plain", "Set-Cookie": ["a=1", "b=2"]}}`)
+		require.NoError(t, err)
+		assert.Equal(t, mock.Headers{
+			"Content-Type": {"text/plain"},
+			"Set-Cookie":   {"a=1", "b=2"},
+		}, mok.Routes[1].Responses[0].Headers)
+
+		err = m.PatchResponse(context.Background(), "mockid", "routeid2", "responseid1", `{"headers": {"X-Count": 1}}`)
+		assert.EqualError(t, err, "header X-Count must be a string or a list of strings")
+	})
+
 	t.Run("mock not found", func(t *testing.T) {
 		err := m.PatchResponse(context.Background(), "random", "", "", `{}`)
 		assert.Error(t, err)
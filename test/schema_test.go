@@ -0,0 +1,25 @@
+package test_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mockingio/engine/mock"
+	"github.com/mockingio/engine/test"
+)
+
+// the schema is published at the root of the repository, so that the editors can reference it.
+// It is not generated if a field has no description, or if a description belongs to no field
+func TestJSONSchema(t *testing.T) {
+	goldenFile := filepath.Join("..", "mock.schema.json")
+
+	schema, err := mock.JSONSchema()
+	require.NoError(t, err)
+
+	test.UpdateGoldenFile(t, goldenFile, schema)
+
+	assert.Equal(t, test.ReadGoldenFile(t, goldenFile), string(schema))
+}